@@ -0,0 +1,72 @@
+package gapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// токен, как его выдает сервис авторизации
+func signToken(secret, header, claims string) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestUserFromContext(t *testing.T) {
+	const secret = "k3y"
+	hs256 := `{"alg":"HS256","typ":"JWT"}`
+	future := time.Now().Add(time.Hour).Unix()
+	valid := signToken(secret, hs256, fmt.Sprintf(`{"sub":"41","exp":%d}`, future))
+
+	tests := []struct {
+		name          string
+		secret        string
+		authorization []string
+		wantUser      int64
+	}{
+		{name: "valid token", secret: secret, authorization: []string{"Bearer " + valid}, wantUser: 41},
+		{name: "no expiration", secret: secret, authorization: []string{"Bearer " + signToken(secret, hs256, `{"sub":"7"}`)}, wantUser: 7},
+		{name: "no metadata", secret: secret},
+		{name: "no secret configured", authorization: []string{"Bearer " + valid}},
+		{name: "no bearer scheme", secret: secret, authorization: []string{valid}},
+		{name: "several tokens", secret: secret, authorization: []string{"Bearer " + valid, "Bearer " + valid}},
+		{name: "other secret", secret: secret, authorization: []string{"Bearer " + signToken("other", hs256, `{"sub":"41"}`)}},
+		{name: "expired", secret: secret, authorization: []string{"Bearer " + signToken(secret, hs256, `{"sub":"41","exp":1}`)}},
+		{name: "other algorithm", secret: secret, authorization: []string{"Bearer " + signToken(secret, `{"alg":"none"}`, `{"sub":"41"}`)}},
+		{name: "no user", secret: secret, authorization: []string{"Bearer " + signToken(secret, hs256, `{"sub":"admin"}`)}},
+		{name: "malformed", secret: secret, authorization: []string{"Bearer abc.def"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			for _, value := range tt.authorization {
+				md.Append("authorization", value)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			server := &Server{authSecret: []byte(tt.secret)}
+			userID, err := server.userFromContext(ctx)
+			if tt.wantUser == 0 {
+				if err == nil {
+					t.Fatalf("user = %d, want error", userID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if userID != tt.wantUser {
+				t.Fatalf("user = %d, want %d", userID, tt.wantUser)
+			}
+		})
+	}
+}
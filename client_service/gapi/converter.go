@@ -1,11 +1,12 @@
 package gapi
 
 import (
-	"clients/protos"
 	"os/user"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
 )
 
 
-func convertOrder(user *user.User) *protos.Order {
-	return &protos.Order{}
+func convertOrder(user *user.User) *events.Order {
+	return &events.Order{}
 }
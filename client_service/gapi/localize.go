@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"clients/i18n"
+	"context"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/metadata"
+)
+
+// gateway передает Accept-Language с префиксом grpcgateway-, gRPC клиенты - как есть
+var languageHeaders = []string{"grpcgateway-accept-language", "accept-language"}
+
+// язык ответа по заголовку Accept-Language запроса
+func requestLanguage(ctx context.Context) language.Tag {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return i18n.Language("")
+	}
+
+	for _, header := range languageHeaders {
+		if values := md.Get(header); len(values) > 0 {
+			return i18n.Language(values[0])
+		}
+	}
+
+	return i18n.Language("")
+}
+
+// заполняем reason текстом причины на языке запроса, у заказов без кода оставляем сохраненный текст
+func localizeOrders(ctx context.Context, orders ...*events.Order) {
+	lang := requestLanguage(ctx)
+
+	for _, order := range orders {
+		if message := i18n.Reason(order.ReasonCode, lang); message != "" {
+			order.Reason = message
+		}
+	}
+}
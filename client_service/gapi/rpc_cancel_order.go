@@ -0,0 +1,44 @@
+package gapi
+
+import (
+	"clients/protos"
+	repository "clients/reposiroty"
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"github.com/Iowel/app-saga-service/contracts/logging"
+)
+
+func (server *Server) CancelOrder(ctx context.Context, req *protos.CancelOrderRequest) (*events.Order, error) {
+	const op = "gapi.CancelOrder"
+
+	ctx = logging.With(ctx, "order_id", req.OrderId)
+
+	// отменить заказ может только его владелец
+	userID, err := server.userFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid user token: path; %s", op)
+	}
+
+	// помечаем заказ, событие для оркестратора отправит outbox relay
+	order, err := server.orderRepo.RequestCancel(ctx, req.OrderId, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to request order cancel", "error", err)
+		switch {
+		case errors.Is(err, repository.ErrOrderNotFound):
+			return nil, status.Errorf(codes.NotFound, "order not found: path; %s, err: %v", op, err)
+		case errors.Is(err, repository.ErrOrderNotCancellable):
+			return nil, status.Errorf(codes.FailedPrecondition, "order can not be cancelled: path; %s, err: %v", op, err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to cancel order: path; %s, err: %v", op, err)
+	}
+	slog.InfoContext(logging.WithOrder(ctx, order), "order cancel requested")
+	localizeOrders(ctx, order)
+
+	return order, nil
+}
@@ -1,18 +1,16 @@
 package gapi
 
 import (
-	"clients/kafka"
-	"clients/protos"
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"net"
-	"time"
+	"log/slog"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/proto"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"github.com/Iowel/app-saga-service/contracts/logging"
 )
 
 var (
@@ -22,72 +20,61 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 )
 
-func (server *Server) CreateOrder(ctx context.Context, req *protos.Order) (*protos.Order, error) {
+func (server *Server) CreateOrder(ctx context.Context, req *events.Order) (*events.Order, error) {
 	const op = "gapi.CreateOrder"
 
-	// создаем заказ в репозитории
+	items, err := normalizeItems(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order items: path; %s, err: %v", op, err)
+	}
+	req.Items = items
+	ctx = logging.WithOrder(ctx, req)
+
+	// создаем заказ в репозитории, событие для саги отправит outbox relay
 	order, err := server.orderRepo.CreateOrder(ctx, req)
 	if err != nil {
+		slog.ErrorContext(ctx, "failed to create order", "error", err)
 		if errors.Is(err, ErrUserNotFound) {
 			return nil, status.Errorf(codes.NotFound, "user not found: path; %s, err: %v", op, err)
 		}
 		return nil, status.Errorf(codes.Internal, "failed to find user: path; %s, err: %v", op, err)
 	}
-
-	// формируем респонс для кафки
-	kafkaResponse := &protos.Order{
-		UserID:     order.UserID,
-		ProductSKU: order.ProductSKU,
-		OrderID:    order.OrderID,
-	}
-
-	// ждем кафку
-	err = waitForKafka("kafka:29092", 10)
-	if err != nil {
-		return nil, status.Errorf(codes.Unavailable, "kafka unavailable: path; %s, err: %v", op, err)
-	}
-
-	brokers := []string{"kafka:29092"}
-
-	// если дождались кафку то запускаем
-	syncProducer, err := kafka.NewSyncProducer(brokers)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create Kafka producer: path; %s, err: %v", op, err)
-	}
-	defer syncProducer.Close()
-
-
-	// маршалим сообщение в протобуф
-	msg, err := proto.Marshal(kafkaResponse)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to marshal message: path; %s, err: %v", op, err)
-	}
-
-	// отправляем сообщение в кафку
-	err = kafka.SendMessage(syncProducer, "create_order", msg)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to send Kafka message: path; %s, err: %v", op, err)
-	}
+	slog.InfoContext(logging.WithOrder(ctx, order), "order created")
 
 	//  ответ для gRPC
-	gRPCResponse := &protos.Order{
-		Status: "order submitted",
+	gRPCResponse := &events.Order{
+		OrderID:    order.OrderID,
+		Status:     order.Status,
+		StatusCode: order.StatusCode,
 	}
 
 	return gRPCResponse, nil
 }
 
-// ждем кафку
-func waitForKafka(addr string, maxRetries int) error {
-	for i := 0; i < maxRetries; i++ {
-		conn, err := net.DialTimeout("tcp", addr, 3*time.Second)
-		if err == nil {
-			_ = conn.Close()
-			log.Println("Successfully connected to Kafka")
-			return nil
+// приводим позиции заказа к единому виду: заказ из одного ProductSKU превращаем в позицию,
+// одинаковые товары объединяем
+func normalizeItems(order *events.Order) ([]*events.OrderItem, error) {
+	if len(order.Items) == 0 {
+		if order.ProductSKU == 0 {
+			return nil, errors.New("order has no items")
+		}
+		return []*events.OrderItem{{Sku: order.ProductSKU, Quantity: 1}}, nil
+	}
+
+	var items []*events.OrderItem
+	bySku := make(map[int64]*events.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid quantity %d for sku %d", item.Quantity, item.Sku)
+		}
+		if existing, ok := bySku[item.Sku]; ok {
+			existing.Quantity += item.Quantity
+			continue
 		}
-		log.Printf("Kafka not ready, retrying... (%d/%d)\n", i+1, maxRetries)
-		time.Sleep(3 * time.Second)
+		merged := &events.OrderItem{Sku: item.Sku, Quantity: item.Quantity}
+		bySku[item.Sku] = merged
+		items = append(items, merged)
 	}
-	return fmt.Errorf("could not connect to Kafka at %s", addr)
+
+	return items, nil
 }
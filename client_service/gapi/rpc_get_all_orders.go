@@ -2,20 +2,23 @@ package gapi
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"clients/protos"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
 )
 
-func (server *Server) GetOrder(ctx context.Context, req *protos.OrderRequest) (*protos.Order, error) {
+func (server *Server) GetOrder(ctx context.Context, req *protos.OrderRequest) (*events.Order, error) {
 	const op = "gapi.GetOrder"
 
-	log.Println(req.OrderId)
+	slog.DebugContext(ctx, "get order", "order_id", req.OrderId)
 
 	order, err := server.orderRepo.GetOrders(ctx, req.OrderId)
 	if err != nil {
 		return nil, err
 	}
+	localizeOrders(ctx, order)
 
 	return order, nil
 
@@ -14,6 +14,7 @@ func (server *Server) GetAllOrders(ctx context.Context, req *protos.GetAllOrders
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to get products: %w", op, err)
 	}
+	localizeOrders(ctx, orders...)
 
 	return &protos.GetAllOrdersResponse{
 		Orders: orders,
@@ -28,6 +29,7 @@ func (server *Server) GetOrdersByUser(ctx context.Context, req *protos.GetOrders
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to get products: %w", op, err)
 	}
+	localizeOrders(ctx, orders...)
 
 	return &protos.GetOrdersByUserResponse{
 		Orders: orders,
@@ -0,0 +1,36 @@
+package gapi
+
+import (
+	"clients/i18n"
+	"clients/protos"
+	repository "clients/reposiroty"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetSagaTrace(ctx context.Context, req *protos.GetSagaTraceRequest) (*protos.SagaTrace, error) {
+	const op = "gapi.GetSagaTrace"
+
+	// ход саги видит только владелец заказа
+	userID, err := server.userFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid user token: path; %s", op)
+	}
+
+	trace, err := server.orderRepo.GetSagaTrace(ctx, req.OrderId, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSagaNotFound) {
+			return nil, status.Errorf(codes.NotFound, "saga not found: path; %s, err: %v", op, err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get saga trace: path; %s, err: %v", op, err)
+	}
+
+	if message := i18n.Reason(trace.ReasonCode, requestLanguage(ctx)); message != "" {
+		trace.Reason = message
+	}
+
+	return trace, nil
+}
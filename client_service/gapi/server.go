@@ -9,10 +9,13 @@ import (
 type Server struct {
 	protos.UnimplementedOrderServiceServer
 	orderRepo *repository.OrderRepository
+	// ключ проверки токенов пользователей
+	authSecret []byte
 }
 
-func NewServer(orderRepo *repository.OrderRepository) (*Server, error) {
+func NewServer(orderRepo *repository.OrderRepository, authSecret string) (*Server, error) {
 	return &Server{
-		orderRepo: orderRepo,
+		orderRepo:  orderRepo,
+		authSecret: []byte(authSecret),
 	}, nil
 }
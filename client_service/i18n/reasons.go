@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"golang.org/x/text/language"
+)
+
+// языки каталога, первый - язык по умолчанию
+var supported = []language.Tag{language.Russian, language.English}
+
+var matcher = language.NewMatcher(supported)
+
+// тексты причин по коду и языку
+var reasons = map[events.ReasonCode]map[language.Tag]string{
+	events.ReasonCode_REASON_CODE_COMPLETED: {
+		language.Russian: "Товар успешно оплачен",
+		language.English: "Order paid successfully",
+	},
+	events.ReasonCode_REASON_CODE_OUT_OF_STOCK: {
+		language.Russian: "Товар закончился",
+		language.English: "Product is out of stock",
+	},
+	events.ReasonCode_REASON_CODE_INSUFFICIENT_FUNDS: {
+		language.Russian: "Недостаточно средств",
+		language.English: "Insufficient funds",
+	},
+	events.ReasonCode_REASON_CODE_COMMIT_FAILED: {
+		language.Russian: "Не удалось завершить заказ",
+		language.English: "Order could not be completed",
+	},
+	events.ReasonCode_REASON_CODE_TIMEOUT: {
+		language.Russian: "Время ожидания истекло",
+		language.English: "Order timed out",
+	},
+	events.ReasonCode_REASON_CODE_CANCELLED_BY_USER: {
+		language.Russian: "Отменен пользователем",
+		language.English: "Cancelled by user",
+	},
+	events.ReasonCode_REASON_CODE_CANCELLED_BY_OPERATOR: {
+		language.Russian: "Заказ отменен оператором",
+		language.English: "Cancelled by operator",
+	},
+}
+
+// Language выбирает язык каталога по заголовку Accept-Language, без заголовка - русский
+func Language(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return supported[0]
+	}
+
+	_, index, _ := matcher.Match(tags...)
+	return supported[index]
+}
+
+// Reason возвращает текст причины на языке lang, для неизвестного кода - пустую строку
+func Reason(code events.ReasonCode, lang language.Tag) string {
+	messages, ok := reasons[code]
+	if !ok {
+		return ""
+	}
+	if message, ok := messages[lang]; ok {
+		return message
+	}
+	return messages[supported[0]]
+}
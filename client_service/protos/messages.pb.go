@@ -7,6 +7,7 @@
 package protos
 
 import (
+	events "github.com/Iowel/app-saga-service/contracts/events"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -22,33 +23,27 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Сообщение для заказа
-type Order struct {
+type OrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserID        int64                  `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,2,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
-	ProductSKU    int64                  `protobuf:"varint,3,opt,name=ProductSKU,proto3" json:"ProductSKU,omitempty"`
-	OrderID       int64                  `protobuf:"varint,4,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Order) Reset() {
-	*x = Order{}
+func (x *OrderRequest) Reset() {
+	*x = OrderRequest{}
 	mi := &file_messages_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Order) String() string {
+func (x *OrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Order) ProtoMessage() {}
+func (*OrderRequest) ProtoMessage() {}
 
-func (x *Order) ProtoReflect() protoreflect.Message {
+func (x *OrderRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_messages_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -60,80 +55,84 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Order.ProtoReflect.Descriptor instead.
-func (*Order) Descriptor() ([]byte, []int) {
+// Deprecated: Use OrderRequest.ProtoReflect.Descriptor instead.
+func (*OrderRequest) Descriptor() ([]byte, []int) {
 	return file_messages_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *Order) GetUserID() int64 {
+func (x *OrderRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.UserID
+		return x.OrderId
 	}
 	return 0
 }
 
-func (x *Order) GetTimestamp() int64 {
-	if x != nil {
-		return x.Timestamp
-	}
-	return 0
+type OrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *events.Order          `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Order) GetProductSKU() int64 {
-	if x != nil {
-		return x.ProductSKU
-	}
-	return 0
+func (x *OrderResponse) Reset() {
+	*x = OrderResponse{}
+	mi := &file_messages_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Order) GetOrderID() int64 {
-	if x != nil {
-		return x.OrderID
-	}
-	return 0
+func (x *OrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Order) GetStatus() string {
+func (*OrderResponse) ProtoMessage() {}
+
+func (x *OrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[1]
 	if x != nil {
-		return x.Status
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
+func (*OrderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *Order) GetReason() string {
+func (x *OrderResponse) GetOrder() *events.Order {
 	if x != nil {
-		return x.Reason
+		return x.Order
 	}
-	return ""
+	return nil
 }
 
-// Сообщение для продукта в заказе
-type Product struct {
+type CancelOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sku           int64                  `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
-	Price         int64                  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
-	Cnt           int64                  `protobuf:"varint,3,opt,name=cnt,proto3" json:"cnt,omitempty"`
-	Avatar        string                 `protobuf:"bytes,4,opt,name=avatar,proto3" json:"avatar,omitempty"`
-	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Product) Reset() {
-	*x = Product{}
-	mi := &file_messages_proto_msgTypes[1]
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_messages_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Product) String() string {
+func (x *CancelOrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Product) ProtoMessage() {}
+func (*CancelOrderRequest) ProtoMessage() {}
 
-func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[1]
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -144,71 +143,94 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Product.ProtoReflect.Descriptor instead.
-func (*Product) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Product) GetSku() int64 {
+func (x *CancelOrderRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.Sku
+		return x.OrderId
 	}
 	return 0
 }
 
-func (x *Product) GetPrice() int64 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
+type GetSagaTraceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Product) GetCnt() int64 {
-	if x != nil {
-		return x.Cnt
-	}
-	return 0
+func (x *GetSagaTraceRequest) Reset() {
+	*x = GetSagaTraceRequest{}
+	mi := &file_messages_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSagaTraceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Product) GetAvatar() string {
+func (*GetSagaTraceRequest) ProtoMessage() {}
+
+func (x *GetSagaTraceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[3]
 	if x != nil {
-		return x.Avatar
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Product) GetName() string {
+// Deprecated: Use GetSagaTraceRequest.ProtoReflect.Descriptor instead.
+func (*GetSagaTraceRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSagaTraceRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.Name
+		return x.OrderId
 	}
-	return ""
+	return 0
 }
 
-type OrderWithProduct struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Order             *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
-	Product           *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
-	Available         bool                   `protobuf:"varint,3,opt,name=Available,proto3" json:"Available,omitempty"`
-	BalanceSufficient bool                   `protobuf:"varint,4,opt,name=balanceSufficient,proto3" json:"balanceSufficient,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+// Переход саги заказа
+type SagaTraceStep struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	FromState string                 `protobuf:"bytes,1,opt,name=from_state,json=fromState,proto3" json:"from_state,omitempty"`
+	ToState   string                 `protobuf:"bytes,2,opt,name=to_state,json=toState,proto3" json:"to_state,omitempty"`
+	// топик сообщения, которое вызвало переход
+	Topic string `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	// результат проверки ответа участника, например available=true
+	Decision string `protobuf:"bytes,4,opt,name=decision,proto3" json:"decision,omitempty"`
+	// компенсации, отправленные на этом переходе
+	Compensations []string `protobuf:"bytes,5,rep,name=compensations,proto3" json:"compensations,omitempty"`
+	// время перехода в формате RFC 3339
+	CreatedAt     string `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderWithProduct) Reset() {
-	*x = OrderWithProduct{}
-	mi := &file_messages_proto_msgTypes[2]
+func (x *SagaTraceStep) Reset() {
+	*x = SagaTraceStep{}
+	mi := &file_messages_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderWithProduct) String() string {
+func (x *SagaTraceStep) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderWithProduct) ProtoMessage() {}
+func (*SagaTraceStep) ProtoMessage() {}
 
-func (x *OrderWithProduct) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[2]
+func (x *SagaTraceStep) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -219,61 +241,80 @@ func (x *OrderWithProduct) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderWithProduct.ProtoReflect.Descriptor instead.
-func (*OrderWithProduct) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use SagaTraceStep.ProtoReflect.Descriptor instead.
+func (*SagaTraceStep) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *OrderWithProduct) GetOrder() *Order {
+func (x *SagaTraceStep) GetFromState() string {
 	if x != nil {
-		return x.Order
+		return x.FromState
 	}
-	return nil
+	return ""
 }
 
-func (x *OrderWithProduct) GetProduct() *Product {
+func (x *SagaTraceStep) GetToState() string {
 	if x != nil {
-		return x.Product
+		return x.ToState
 	}
-	return nil
+	return ""
 }
 
-func (x *OrderWithProduct) GetAvailable() bool {
+func (x *SagaTraceStep) GetTopic() string {
 	if x != nil {
-		return x.Available
+		return x.Topic
 	}
-	return false
+	return ""
 }
 
-func (x *OrderWithProduct) GetBalanceSufficient() bool {
+func (x *SagaTraceStep) GetDecision() string {
 	if x != nil {
-		return x.BalanceSufficient
+		return x.Decision
 	}
-	return false
+	return ""
 }
 
-type OrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+func (x *SagaTraceStep) GetCompensations() []string {
+	if x != nil {
+		return x.Compensations
+	}
+	return nil
+}
+
+func (x *SagaTraceStep) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// Смена статуса заказа
+type OrderStatusChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// пустой у только что созданного заказа
+	FromStatus events.OrderStatus `protobuf:"varint,1,opt,name=from_status,json=fromStatus,proto3,enum=events.OrderStatus" json:"from_status,omitempty"`
+	ToStatus   events.OrderStatus `protobuf:"varint,2,opt,name=to_status,json=toStatus,proto3,enum=events.OrderStatus" json:"to_status,omitempty"`
+	// время смены в формате RFC 3339
+	CreatedAt     string `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderRequest) Reset() {
-	*x = OrderRequest{}
-	mi := &file_messages_proto_msgTypes[3]
+func (x *OrderStatusChange) Reset() {
+	*x = OrderStatusChange{}
+	mi := &file_messages_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderRequest) String() string {
+func (x *OrderStatusChange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderRequest) ProtoMessage() {}
+func (*OrderStatusChange) ProtoMessage() {}
 
-func (x *OrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[3]
+func (x *OrderStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -284,40 +325,68 @@ func (x *OrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderRequest.ProtoReflect.Descriptor instead.
-func (*OrderRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use OrderStatusChange.ProtoReflect.Descriptor instead.
+func (*OrderStatusChange) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *OrderRequest) GetOrderId() int64 {
+func (x *OrderStatusChange) GetFromStatus() events.OrderStatus {
 	if x != nil {
-		return x.OrderId
+		return x.FromStatus
 	}
-	return 0
+	return events.OrderStatus(0)
 }
 
-type OrderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+func (x *OrderStatusChange) GetToStatus() events.OrderStatus {
+	if x != nil {
+		return x.ToStatus
+	}
+	return events.OrderStatus(0)
+}
+
+func (x *OrderStatusChange) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// История саги заказа
+type SagaTrace struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Name    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	State   string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	// текст статуса для клиентов без status_code
+	//
+	// Deprecated: Marked as deprecated in messages.proto.
+	Status        string               `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string               `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     string               `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string               `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Steps         []*SagaTraceStep     `protobuf:"bytes,8,rep,name=steps,proto3" json:"steps,omitempty"`
+	StatusHistory []*OrderStatusChange `protobuf:"bytes,9,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	ReasonCode    events.ReasonCode    `protobuf:"varint,10,opt,name=reason_code,json=reasonCode,proto3,enum=events.ReasonCode" json:"reason_code,omitempty"`
+	StatusCode    events.OrderStatus   `protobuf:"varint,11,opt,name=status_code,json=statusCode,proto3,enum=events.OrderStatus" json:"status_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderResponse) Reset() {
-	*x = OrderResponse{}
-	mi := &file_messages_proto_msgTypes[4]
+func (x *SagaTrace) Reset() {
+	*x = SagaTrace{}
+	mi := &file_messages_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderResponse) String() string {
+func (x *SagaTrace) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderResponse) ProtoMessage() {}
+func (*SagaTrace) ProtoMessage() {}
 
-func (x *OrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[4]
+func (x *SagaTrace) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -328,18 +397,89 @@ func (x *OrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
-func (*OrderResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use SagaTrace.ProtoReflect.Descriptor instead.
+func (*SagaTrace) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *OrderResponse) GetOrder() *Order {
+func (x *SagaTrace) GetOrderId() int64 {
 	if x != nil {
-		return x.Order
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *SagaTrace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SagaTrace) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in messages.proto.
+func (x *SagaTrace) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SagaTrace) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SagaTrace) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *SagaTrace) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *SagaTrace) GetSteps() []*SagaTraceStep {
+	if x != nil {
+		return x.Steps
 	}
 	return nil
 }
 
+func (x *SagaTrace) GetStatusHistory() []*OrderStatusChange {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+func (x *SagaTrace) GetReasonCode() events.ReasonCode {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return events.ReasonCode(0)
+}
+
+func (x *SagaTrace) GetStatusCode() events.OrderStatus {
+	if x != nil {
+		return x.StatusCode
+	}
+	return events.OrderStatus(0)
+}
+
 type GetAllOrdersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -348,7 +488,7 @@ type GetAllOrdersRequest struct {
 
 func (x *GetAllOrdersRequest) Reset() {
 	*x = GetAllOrdersRequest{}
-	mi := &file_messages_proto_msgTypes[5]
+	mi := &file_messages_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -360,7 +500,7 @@ func (x *GetAllOrdersRequest) String() string {
 func (*GetAllOrdersRequest) ProtoMessage() {}
 
 func (x *GetAllOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[5]
+	mi := &file_messages_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -373,19 +513,19 @@ func (x *GetAllOrdersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllOrdersRequest.ProtoReflect.Descriptor instead.
 func (*GetAllOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{5}
+	return file_messages_proto_rawDescGZIP(), []int{7}
 }
 
 type GetAllOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Orders        []*events.Order        `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllOrdersResponse) Reset() {
 	*x = GetAllOrdersResponse{}
-	mi := &file_messages_proto_msgTypes[6]
+	mi := &file_messages_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -397,7 +537,7 @@ func (x *GetAllOrdersResponse) String() string {
 func (*GetAllOrdersResponse) ProtoMessage() {}
 
 func (x *GetAllOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[6]
+	mi := &file_messages_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -410,10 +550,10 @@ func (x *GetAllOrdersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllOrdersResponse.ProtoReflect.Descriptor instead.
 func (*GetAllOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{6}
+	return file_messages_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetAllOrdersResponse) GetOrders() []*Order {
+func (x *GetAllOrdersResponse) GetOrders() []*events.Order {
 	if x != nil {
 		return x.Orders
 	}
@@ -429,7 +569,7 @@ type GetOrdersByUserRequest struct {
 
 func (x *GetOrdersByUserRequest) Reset() {
 	*x = GetOrdersByUserRequest{}
-	mi := &file_messages_proto_msgTypes[7]
+	mi := &file_messages_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -441,7 +581,7 @@ func (x *GetOrdersByUserRequest) String() string {
 func (*GetOrdersByUserRequest) ProtoMessage() {}
 
 func (x *GetOrdersByUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[7]
+	mi := &file_messages_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -454,7 +594,7 @@ func (x *GetOrdersByUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrdersByUserRequest.ProtoReflect.Descriptor instead.
 func (*GetOrdersByUserRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{7}
+	return file_messages_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetOrdersByUserRequest) GetUserId() int64 {
@@ -466,14 +606,14 @@ func (x *GetOrdersByUserRequest) GetUserId() int64 {
 
 type GetOrdersByUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Orders        []*events.Order        `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetOrdersByUserResponse) Reset() {
 	*x = GetOrdersByUserResponse{}
-	mi := &file_messages_proto_msgTypes[8]
+	mi := &file_messages_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -485,7 +625,7 @@ func (x *GetOrdersByUserResponse) String() string {
 func (*GetOrdersByUserResponse) ProtoMessage() {}
 
 func (x *GetOrdersByUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[8]
+	mi := &file_messages_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -498,10 +638,10 @@ func (x *GetOrdersByUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrdersByUserResponse.ProtoReflect.Descriptor instead.
 func (*GetOrdersByUserResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{8}
+	return file_messages_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetOrdersByUserResponse) GetOrders() []*Order {
+func (x *GetOrdersByUserResponse) GetOrders() []*events.Order {
 	if x != nil {
 		return x.Orders
 	}
@@ -512,42 +652,60 @@ var File_messages_proto protoreflect.FileDescriptor
 
 const file_messages_proto_rawDesc = "" +
 	"\n" +
-	"\x0emessages.proto\x12\x06protos\x1a\x1cgoogle/api/annotations.proto\"\xa7\x01\n" +
-	"\x05Order\x12\x16\n" +
-	"\x06UserID\x18\x01 \x01(\x03R\x06UserID\x12\x1c\n" +
-	"\tTimestamp\x18\x02 \x01(\x03R\tTimestamp\x12\x1e\n" +
-	"\n" +
-	"ProductSKU\x18\x03 \x01(\x03R\n" +
-	"ProductSKU\x12\x18\n" +
-	"\aOrderID\x18\x04 \x01(\x03R\aOrderID\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\x12\x16\n" +
-	"\x06reason\x18\x06 \x01(\tR\x06reason\"o\n" +
-	"\aProduct\x12\x10\n" +
-	"\x03sku\x18\x01 \x01(\x03R\x03sku\x12\x14\n" +
-	"\x05price\x18\x02 \x01(\x03R\x05price\x12\x10\n" +
-	"\x03cnt\x18\x03 \x01(\x03R\x03cnt\x12\x16\n" +
-	"\x06avatar\x18\x04 \x01(\tR\x06avatar\x12\x12\n" +
-	"\x04name\x18\x05 \x01(\tR\x04name\"\xae\x01\n" +
-	"\x10OrderWithProduct\x12#\n" +
-	"\x05order\x18\x01 \x01(\v2\r.protos.OrderR\x05order\x12)\n" +
-	"\aproduct\x18\x02 \x01(\v2\x0f.protos.ProductR\aproduct\x12\x1c\n" +
-	"\tAvailable\x18\x03 \x01(\bR\tAvailable\x12,\n" +
-	"\x11balanceSufficient\x18\x04 \x01(\bR\x11balanceSufficient\")\n" +
+	"\x0emessages.proto\x12\x06protos\x1a\x1cgoogle/api/annotations.proto\x1a\x15events/messages.proto\")\n" +
 	"\fOrderRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\x03R\aorderId\"4\n" +
 	"\rOrderResponse\x12#\n" +
-	"\x05order\x18\x01 \x01(\v2\r.protos.OrderR\x05order\"\x15\n" +
+	"\x05order\x18\x01 \x01(\v2\r.events.OrderR\x05order\"/\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\"0\n" +
+	"\x13GetSagaTraceRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\"\xc0\x01\n" +
+	"\rSagaTraceStep\x12\x1d\n" +
+	"\n" +
+	"from_state\x18\x01 \x01(\tR\tfromState\x12\x19\n" +
+	"\bto_state\x18\x02 \x01(\tR\atoState\x12\x14\n" +
+	"\x05topic\x18\x03 \x01(\tR\x05topic\x12\x1a\n" +
+	"\bdecision\x18\x04 \x01(\tR\bdecision\x12$\n" +
+	"\rcompensations\x18\x05 \x03(\tR\rcompensations\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\"\x9a\x01\n" +
+	"\x11OrderStatusChange\x124\n" +
+	"\vfrom_status\x18\x01 \x01(\x0e2\x13.events.OrderStatusR\n" +
+	"fromStatus\x120\n" +
+	"\tto_status\x18\x02 \x01(\x0e2\x13.events.OrderStatusR\btoStatus\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\"\x9c\x03\n" +
+	"\tSagaTrace\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x1a\n" +
+	"\x06status\x18\x04 \x01(\tB\x02\x18\x01R\x06status\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12+\n" +
+	"\x05steps\x18\b \x03(\v2\x15.protos.SagaTraceStepR\x05steps\x12@\n" +
+	"\x0estatus_history\x18\t \x03(\v2\x19.protos.OrderStatusChangeR\rstatusHistory\x123\n" +
+	"\vreason_code\x18\n" +
+	" \x01(\x0e2\x12.events.ReasonCodeR\n" +
+	"reasonCode\x124\n" +
+	"\vstatus_code\x18\v \x01(\x0e2\x13.events.OrderStatusR\n" +
+	"statusCode\"\x15\n" +
 	"\x13GetAllOrdersRequest\"=\n" +
 	"\x14GetAllOrdersResponse\x12%\n" +
-	"\x06orders\x18\x01 \x03(\v2\r.protos.OrderR\x06orders\"1\n" +
+	"\x06orders\x18\x01 \x03(\v2\r.events.OrderR\x06orders\"1\n" +
 	"\x16GetOrdersByUserRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x03R\x06userId\"@\n" +
 	"\x17GetOrdersByUserResponse\x12%\n" +
-	"\x06orders\x18\x01 \x03(\v2\r.protos.OrderR\x06orders2\xfc\x02\n" +
+	"\x06orders\x18\x01 \x03(\v2\r.events.OrderR\x06orders2\xc4\x04\n" +
 	"\fOrderService\x12B\n" +
-	"\vCreateOrder\x12\r.protos.Order\x1a\r.protos.Order\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"\vCreateOrder\x12\r.events.Order\x1a\r.events.Order\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
 	"/v1/orders\x12R\n" +
-	"\bGetOrder\x12\x14.protos.OrderRequest\x1a\r.protos.Order\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/-get-order/{order_id}\x12]\n" +
+	"\bGetOrder\x12\x14.protos.OrderRequest\x1a\r.events.Order\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/-get-order/{order_id}\x12a\n" +
+	"\vCancelOrder\x12\x1a.protos.CancelOrderRequest\x1a\r.events.Order\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/orders/{order_id}/cancel\x12c\n" +
+	"\fGetSagaTrace\x12\x1b.protos.GetSagaTraceRequest\x1a\x11.protos.SagaTrace\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/orders/{order_id}/trace\x12]\n" +
 	"\fGetAllOrders\x12\x1b.protos.GetAllOrdersRequest\x1a\x1c.protos.GetAllOrdersResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
 	"/v1/orders\x12u\n" +
 	"\x0fGetOrdersByUser\x12\x1e.protos.GetOrdersByUserRequest\x1a\x1f.protos.GetOrdersByUserResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/orders/user/{user_id}B\x17Z\x15clients/protos;protosb\x06proto3"
@@ -564,37 +722,50 @@ func file_messages_proto_rawDescGZIP() []byte {
 	return file_messages_proto_rawDescData
 }
 
-var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_messages_proto_goTypes = []any{
-	(*Order)(nil),                   // 0: protos.Order
-	(*Product)(nil),                 // 1: protos.Product
-	(*OrderWithProduct)(nil),        // 2: protos.OrderWithProduct
-	(*OrderRequest)(nil),            // 3: protos.OrderRequest
-	(*OrderResponse)(nil),           // 4: protos.OrderResponse
-	(*GetAllOrdersRequest)(nil),     // 5: protos.GetAllOrdersRequest
-	(*GetAllOrdersResponse)(nil),    // 6: protos.GetAllOrdersResponse
-	(*GetOrdersByUserRequest)(nil),  // 7: protos.GetOrdersByUserRequest
-	(*GetOrdersByUserResponse)(nil), // 8: protos.GetOrdersByUserResponse
+	(*OrderRequest)(nil),            // 0: protos.OrderRequest
+	(*OrderResponse)(nil),           // 1: protos.OrderResponse
+	(*CancelOrderRequest)(nil),      // 2: protos.CancelOrderRequest
+	(*GetSagaTraceRequest)(nil),     // 3: protos.GetSagaTraceRequest
+	(*SagaTraceStep)(nil),           // 4: protos.SagaTraceStep
+	(*OrderStatusChange)(nil),       // 5: protos.OrderStatusChange
+	(*SagaTrace)(nil),               // 6: protos.SagaTrace
+	(*GetAllOrdersRequest)(nil),     // 7: protos.GetAllOrdersRequest
+	(*GetAllOrdersResponse)(nil),    // 8: protos.GetAllOrdersResponse
+	(*GetOrdersByUserRequest)(nil),  // 9: protos.GetOrdersByUserRequest
+	(*GetOrdersByUserResponse)(nil), // 10: protos.GetOrdersByUserResponse
+	(*events.Order)(nil),            // 11: events.Order
+	(events.OrderStatus)(0),         // 12: events.OrderStatus
+	(events.ReasonCode)(0),          // 13: events.ReasonCode
 }
 var file_messages_proto_depIdxs = []int32{
-	0, // 0: protos.OrderWithProduct.order:type_name -> protos.Order
-	1, // 1: protos.OrderWithProduct.product:type_name -> protos.Product
-	0, // 2: protos.OrderResponse.order:type_name -> protos.Order
-	0, // 3: protos.GetAllOrdersResponse.orders:type_name -> protos.Order
-	0, // 4: protos.GetOrdersByUserResponse.orders:type_name -> protos.Order
-	0, // 5: protos.OrderService.CreateOrder:input_type -> protos.Order
-	3, // 6: protos.OrderService.GetOrder:input_type -> protos.OrderRequest
-	5, // 7: protos.OrderService.GetAllOrders:input_type -> protos.GetAllOrdersRequest
-	7, // 8: protos.OrderService.GetOrdersByUser:input_type -> protos.GetOrdersByUserRequest
-	0, // 9: protos.OrderService.CreateOrder:output_type -> protos.Order
-	0, // 10: protos.OrderService.GetOrder:output_type -> protos.Order
-	6, // 11: protos.OrderService.GetAllOrders:output_type -> protos.GetAllOrdersResponse
-	8, // 12: protos.OrderService.GetOrdersByUser:output_type -> protos.GetOrdersByUserResponse
-	9, // [9:13] is the sub-list for method output_type
-	5, // [5:9] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	11, // 0: protos.OrderResponse.order:type_name -> events.Order
+	12, // 1: protos.OrderStatusChange.from_status:type_name -> events.OrderStatus
+	12, // 2: protos.OrderStatusChange.to_status:type_name -> events.OrderStatus
+	4,  // 3: protos.SagaTrace.steps:type_name -> protos.SagaTraceStep
+	5,  // 4: protos.SagaTrace.status_history:type_name -> protos.OrderStatusChange
+	13, // 5: protos.SagaTrace.reason_code:type_name -> events.ReasonCode
+	12, // 6: protos.SagaTrace.status_code:type_name -> events.OrderStatus
+	11, // 7: protos.GetAllOrdersResponse.orders:type_name -> events.Order
+	11, // 8: protos.GetOrdersByUserResponse.orders:type_name -> events.Order
+	11, // 9: protos.OrderService.CreateOrder:input_type -> events.Order
+	0,  // 10: protos.OrderService.GetOrder:input_type -> protos.OrderRequest
+	2,  // 11: protos.OrderService.CancelOrder:input_type -> protos.CancelOrderRequest
+	3,  // 12: protos.OrderService.GetSagaTrace:input_type -> protos.GetSagaTraceRequest
+	7,  // 13: protos.OrderService.GetAllOrders:input_type -> protos.GetAllOrdersRequest
+	9,  // 14: protos.OrderService.GetOrdersByUser:input_type -> protos.GetOrdersByUserRequest
+	11, // 15: protos.OrderService.CreateOrder:output_type -> events.Order
+	11, // 16: protos.OrderService.GetOrder:output_type -> events.Order
+	11, // 17: protos.OrderService.CancelOrder:output_type -> events.Order
+	6,  // 18: protos.OrderService.GetSagaTrace:output_type -> protos.SagaTrace
+	8,  // 19: protos.OrderService.GetAllOrders:output_type -> protos.GetAllOrdersResponse
+	10, // 20: protos.OrderService.GetOrdersByUser:output_type -> protos.GetOrdersByUserResponse
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -608,7 +779,7 @@ func file_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messages_proto_rawDesc), len(file_messages_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -8,6 +8,7 @@ package protos
 
 import (
 	context "context"
+	events "github.com/Iowel/app-saga-service/contracts/events"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -21,6 +22,8 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	OrderService_CreateOrder_FullMethodName     = "/protos.OrderService/CreateOrder"
 	OrderService_GetOrder_FullMethodName        = "/protos.OrderService/GetOrder"
+	OrderService_CancelOrder_FullMethodName     = "/protos.OrderService/CancelOrder"
+	OrderService_GetSagaTrace_FullMethodName    = "/protos.OrderService/GetSagaTrace"
 	OrderService_GetAllOrders_FullMethodName    = "/protos.OrderService/GetAllOrders"
 	OrderService_GetOrdersByUser_FullMethodName = "/protos.OrderService/GetOrdersByUser"
 )
@@ -31,8 +34,12 @@ const (
 //
 // Сервис для работы с заказами
 type OrderServiceClient interface {
-	CreateOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error)
-	GetOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*Order, error)
+	CreateOrder(ctx context.Context, in *events.Order, opts ...grpc.CallOption) (*events.Order, error)
+	GetOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*events.Order, error)
+	// Отмена заказа пользователем, пока сага не завершена
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*events.Order, error)
+	// История саги заказа со всеми переходами
+	GetSagaTrace(ctx context.Context, in *GetSagaTraceRequest, opts ...grpc.CallOption) (*SagaTrace, error)
 	GetAllOrders(ctx context.Context, in *GetAllOrdersRequest, opts ...grpc.CallOption) (*GetAllOrdersResponse, error)
 	GetOrdersByUser(ctx context.Context, in *GetOrdersByUserRequest, opts ...grpc.CallOption) (*GetOrdersByUserResponse, error)
 }
@@ -45,9 +52,9 @@ func NewOrderServiceClient(cc grpc.ClientConnInterface) OrderServiceClient {
 	return &orderServiceClient{cc}
 }
 
-func (c *orderServiceClient) CreateOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error) {
+func (c *orderServiceClient) CreateOrder(ctx context.Context, in *events.Order, opts ...grpc.CallOption) (*events.Order, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Order)
+	out := new(events.Order)
 	err := c.cc.Invoke(ctx, OrderService_CreateOrder_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -55,9 +62,9 @@ func (c *orderServiceClient) CreateOrder(ctx context.Context, in *Order, opts ..
 	return out, nil
 }
 
-func (c *orderServiceClient) GetOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*Order, error) {
+func (c *orderServiceClient) GetOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*events.Order, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Order)
+	out := new(events.Order)
 	err := c.cc.Invoke(ctx, OrderService_GetOrder_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -65,6 +72,26 @@ func (c *orderServiceClient) GetOrder(ctx context.Context, in *OrderRequest, opt
 	return out, nil
 }
 
+func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*events.Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(events.Order)
+	err := c.cc.Invoke(ctx, OrderService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetSagaTrace(ctx context.Context, in *GetSagaTraceRequest, opts ...grpc.CallOption) (*SagaTrace, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SagaTrace)
+	err := c.cc.Invoke(ctx, OrderService_GetSagaTrace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) GetAllOrders(ctx context.Context, in *GetAllOrdersRequest, opts ...grpc.CallOption) (*GetAllOrdersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAllOrdersResponse)
@@ -91,8 +118,12 @@ func (c *orderServiceClient) GetOrdersByUser(ctx context.Context, in *GetOrdersB
 //
 // Сервис для работы с заказами
 type OrderServiceServer interface {
-	CreateOrder(context.Context, *Order) (*Order, error)
-	GetOrder(context.Context, *OrderRequest) (*Order, error)
+	CreateOrder(context.Context, *events.Order) (*events.Order, error)
+	GetOrder(context.Context, *OrderRequest) (*events.Order, error)
+	// Отмена заказа пользователем, пока сага не завершена
+	CancelOrder(context.Context, *CancelOrderRequest) (*events.Order, error)
+	// История саги заказа со всеми переходами
+	GetSagaTrace(context.Context, *GetSagaTraceRequest) (*SagaTrace, error)
 	GetAllOrders(context.Context, *GetAllOrdersRequest) (*GetAllOrdersResponse, error)
 	GetOrdersByUser(context.Context, *GetOrdersByUserRequest) (*GetOrdersByUserResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
@@ -105,12 +136,18 @@ type OrderServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedOrderServiceServer struct{}
 
-func (UnimplementedOrderServiceServer) CreateOrder(context.Context, *Order) (*Order, error) {
+func (UnimplementedOrderServiceServer) CreateOrder(context.Context, *events.Order) (*events.Order, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateOrder not implemented")
 }
-func (UnimplementedOrderServiceServer) GetOrder(context.Context, *OrderRequest) (*Order, error) {
+func (UnimplementedOrderServiceServer) GetOrder(context.Context, *OrderRequest) (*events.Order, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*events.Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) GetSagaTrace(context.Context, *GetSagaTraceRequest) (*SagaTrace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSagaTrace not implemented")
+}
 func (UnimplementedOrderServiceServer) GetAllOrders(context.Context, *GetAllOrdersRequest) (*GetAllOrdersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllOrders not implemented")
 }
@@ -139,7 +176,7 @@ func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer)
 }
 
 func _OrderService_CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Order)
+	in := new(events.Order)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -151,7 +188,7 @@ func _OrderService_CreateOrder_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: OrderService_CreateOrder_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrderServiceServer).CreateOrder(ctx, req.(*Order))
+		return srv.(OrderServiceServer).CreateOrder(ctx, req.(*events.Order))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -174,6 +211,42 @@ func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetSagaTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSagaTraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetSagaTrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetSagaTrace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetSagaTrace(ctx, req.(*GetSagaTraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_GetAllOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAllOrdersRequest)
 	if err := dec(in); err != nil {
@@ -225,6 +298,14 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOrder",
 			Handler:    _OrderService_GetOrder_Handler,
 		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _OrderService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "GetSagaTrace",
+			Handler:    _OrderService_GetSagaTrace_Handler,
+		},
 		{
 			MethodName: "GetAllOrders",
 			Handler:    _OrderService_GetAllOrders_Handler,
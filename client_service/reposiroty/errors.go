@@ -0,0 +1,9 @@
+package repository
+
+import "errors"
+
+var (
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrOrderNotCancellable = errors.New("order can not be cancelled")
+	ErrSagaNotFound        = errors.New("saga not found")
+)
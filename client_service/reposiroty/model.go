@@ -7,4 +7,10 @@ type Order struct {
 	ProductSKU int64
 	Status     string
 	Reason     string
+	Items      []OrderItem
+}
+
+type OrderItem struct {
+	SKU      int64
+	Quantity int64
 }
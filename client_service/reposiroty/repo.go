@@ -1,9 +1,16 @@
 package repository
 
 import (
-	"clients/protos"
 	"context"
+	"errors"
 	"fmt"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"github.com/Iowel/app-saga-service/contracts/kafka"
+	"github.com/Iowel/app-saga-service/contracts/outbox"
+	"github.com/Iowel/app-saga-service/contracts/topics"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
 )
 
 type OrderRepository struct {
@@ -14,7 +21,7 @@ func NewOrderRepository(db *Db) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
-func (r *OrderRepository) CreateOrder(ctx context.Context, order *protos.Order) (*protos.Order, error) {
+func (r *OrderRepository) CreateOrder(ctx context.Context, order *events.Order) (*events.Order, error) {
 	// начинаем транзакцию
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
@@ -22,7 +29,12 @@ func (r *OrderRepository) CreateOrder(ctx context.Context, order *protos.Order)
 	}
 	defer tx.Rollback(ctx)
 
-	// вставляем заказ в таблицу orders
+	// в product_sku храним первую позицию для старых клиентов
+	if len(order.Items) > 0 {
+		order.ProductSKU = order.Items[0].Sku
+	}
+
+	// вставляем заказ в таблицу orders, новый заказ всегда ждет саги
 	err = tx.QueryRow(ctx, `
 		INSERT INTO orders (user_id, product_sku, timestamp, status, reason)
 		VALUES ($1, $2, $3, $4, $5)
@@ -31,13 +43,51 @@ func (r *OrderRepository) CreateOrder(ctx context.Context, order *protos.Order)
 		order.UserID,
 		order.ProductSKU,
 		order.Timestamp,
-		order.Status,
+		StatusPending,
 		order.Reason,
 	).Scan(&order.OrderID)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed insert order: %w", err)
 	}
+	setStatus(order, StatusPending)
+
+	// первая запись в истории статусов
+	_, err = tx.Exec(ctx, `
+		INSERT INTO order_status_history (order_id, to_status)
+		VALUES ($1, $2)
+	`, order.OrderID, StatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed insert order status history: %w", err)
+	}
+
+	// вставляем позиции заказа
+	for _, item := range order.Items {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO order_items (order_id, sku, quantity)
+			VALUES ($1, $2, $3)
+		`, order.OrderID, item.Sku, item.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed insert order item: %w", err)
+		}
+	}
+
+	// формируем событие для запуска саги
+	event, err := proto.Marshal(&events.Order{
+		UserID:     order.UserID,
+		ProductSKU: order.ProductSKU,
+		OrderID:    order.OrderID,
+		Items:      order.Items,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order event: %w", err)
+	}
+
+	// событие пишем в outbox в той же транзакции, отправит его relay.
+	// create_order начинает сагу заказа, ее id - id заказа
+	if err := outbox.Insert(kafka.WithSagaID(ctx, order.OrderID), tx, topics.CreateOrder, event); err != nil {
+		return nil, err
+	}
 
 	// фиксируем транзакцию
 	if err := tx.Commit(ctx); err != nil {
@@ -47,7 +97,65 @@ func (r *OrderRepository) CreateOrder(ctx context.Context, order *protos.Order)
 	return order, nil
 }
 
-func (r *OrderRepository) GetOrders(ctx context.Context, orderID int64) (*protos.Order, error) {
+// RequestCancel пишет событие cancel_request в outbox, статус заказа сменит оркестратор после отмены саги.
+// Отменить можно только свой заказ, сага которого еще не завершилась. Чужой заказ не находится,
+// чтобы по ответу нельзя было узнать, какие заказы существуют.
+func (r *OrderRepository) RequestCancel(ctx context.Context, orderID int64, userID int64) (*events.Order, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// блокируем заказ, чтобы отмена не разошлась с завершением саги
+	var order events.Order
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT
+			order_id,
+			user_id,
+			product_sku,
+			status
+		FROM
+			orders
+		WHERE
+			order_id = $1 AND user_id = $2
+		FOR UPDATE
+	`, orderID, userID).Scan(&order.OrderID, &order.UserID, &order.ProductSKU, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if !isCancellable(status) {
+		return nil, fmt.Errorf("%w: order %d has status %s", ErrOrderNotCancellable, orderID, status)
+	}
+	setStatus(&order, status)
+
+	event, err := proto.Marshal(&events.Order{
+		UserID:     order.UserID,
+		ProductSKU: order.ProductSKU,
+		OrderID:    order.OrderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cancel event: %w", err)
+	}
+
+	// оркестратор отменит сагу и запустит компенсации
+	if err := outbox.Insert(kafka.WithSagaID(ctx, orderID), tx, topics.CancelRequest, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return &order, nil
+}
+
+func (r *OrderRepository) GetOrders(ctx context.Context, orderID int64) (*events.Order, error) {
 	row := r.db.Pool.QueryRow(ctx, `
 		SELECT 
 			order_id, 
@@ -55,30 +163,39 @@ func (r *OrderRepository) GetOrders(ctx context.Context, orderID int64) (*protos
 			timestamp, 
 			product_sku, 
 			status, 
-			reason 
+			reason,
+			reason_code
 		FROM
 			orders
 		WHERE
 			order_id = $1
 	`, orderID)
 
-	var order protos.Order
+	var order events.Order
+	var status, reasonCode string
 	err := row.Scan(
 		&order.OrderID,
 		&order.UserID,
 		&order.Timestamp,
 		&order.ProductSKU,
-		&order.Status,
+		&status,
 		&order.Reason,
+		&reasonCode,
 	)
 	if err != nil {
 		return nil, err
 	}
+	setStatus(&order, status)
+	order.ReasonCode = reasonToProto(reasonCode)
+
+	if err := r.loadItems(ctx, []*events.Order{&order}); err != nil {
+		return nil, err
+	}
 
 	return &order, nil
 }
 
-func (r *OrderRepository) GetAllOrders(ctx context.Context) ([]*protos.Order, error) {
+func (r *OrderRepository) GetAllOrders(ctx context.Context) ([]*events.Order, error) {
 	const query = `
         SELECT 
             user_id,
@@ -86,7 +203,8 @@ func (r *OrderRepository) GetAllOrders(ctx context.Context) ([]*protos.Order, er
             product_sku,
             order_id,
             status,
-            reason
+            reason,
+            reason_code
         FROM orders
     `
 
@@ -96,19 +214,23 @@ func (r *OrderRepository) GetAllOrders(ctx context.Context) ([]*protos.Order, er
 	}
 	defer rows.Close()
 
-	var orders []*protos.Order
+	var orders []*events.Order
 	for rows.Next() {
-		var order protos.Order
+		var order events.Order
+		var status, reasonCode string
 		if err := rows.Scan(
 			&order.UserID,
 			&order.Timestamp,
 			&order.ProductSKU,
 			&order.OrderID,
-			&order.Status,
+			&status,
 			&order.Reason,
+			&reasonCode,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan order: %w", err)
 		}
+		setStatus(&order, status)
+		order.ReasonCode = reasonToProto(reasonCode)
 		orders = append(orders, &order)
 	}
 
@@ -116,10 +238,14 @@ func (r *OrderRepository) GetAllOrders(ctx context.Context) ([]*protos.Order, er
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, err
+	}
+
 	return orders, nil
 }
 
-func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) ([]*protos.Order, error) {
+func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) ([]*events.Order, error) {
 	const query = `
         SELECT 
             user_id,
@@ -127,7 +253,8 @@ func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) (
             product_sku,
             order_id,
             status,
-            reason
+            reason,
+            reason_code
         FROM orders
         WHERE user_id = $1
     `
@@ -138,19 +265,23 @@ func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) (
 	}
 	defer rows.Close()
 
-	var orders []*protos.Order
+	var orders []*events.Order
 	for rows.Next() {
-		var order protos.Order
+		var order events.Order
+		var status, reasonCode string
 		if err := rows.Scan(
 			&order.UserID,
 			&order.Timestamp,
 			&order.ProductSKU,
 			&order.OrderID,
-			&order.Status,
+			&status,
 			&order.Reason,
+			&reasonCode,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan order: %w", err)
 		}
+		setStatus(&order, status)
+		order.ReasonCode = reasonToProto(reasonCode)
 		orders = append(orders, &order)
 	}
 
@@ -158,6 +289,10 @@ func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) (
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, err
+	}
+
 	return orders, nil
 }
 
@@ -189,5 +324,68 @@ func (r *OrderRepository) GetOrderRest(ctx context.Context, orderID int) (*Order
 		return nil, err
 	}
 
+	items := []*events.Order{{OrderID: order.OrderID, ProductSKU: order.ProductSKU}}
+	if err := r.loadItems(ctx, items); err != nil {
+		return nil, err
+	}
+	for _, item := range items[0].Items {
+		order.Items = append(order.Items, OrderItem{SKU: item.Sku, Quantity: item.Quantity})
+	}
+
 	return &order, nil
 }
+
+// загружаем позиции заказов одним запросом, для заказов без позиций берем product_sku
+func (r *OrderRepository) loadItems(ctx context.Context, orders []*events.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*events.Order, len(orders))
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		byID[order.OrderID] = order
+		ids = append(ids, order.OrderID)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			order_id,
+			sku,
+			quantity
+		FROM
+			order_items
+		WHERE
+			order_id = ANY($1)
+		ORDER BY
+			order_id, sku
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to query order items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int64
+		var item events.OrderItem
+		if err := rows.Scan(&orderID, &item.Sku, &item.Quantity); err != nil {
+			return fmt.Errorf("failed to scan order item: %w", err)
+		}
+		if order, ok := byID[orderID]; ok {
+			order.Items = append(order.Items, &item)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// заказы, созданные до появления позиций
+	for _, order := range orders {
+		if len(order.Items) == 0 && order.ProductSKU != 0 {
+			order.Items = []*events.OrderItem{{Sku: order.ProductSKU, Quantity: 1}}
+		}
+	}
+
+	return nil
+}
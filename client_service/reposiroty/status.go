@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+)
+
+// статусы заказа, в базе хранятся в типе order_status.
+// Создает заказ клиентский сервис, остальные переходы выполняет order_service
+const (
+	StatusPending   = "pending"
+	StatusReserved  = "reserved"
+	StatusPaid      = "paid"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusRefunded  = "refunded"
+)
+
+// отменить можно только заказ, сага которого еще идет
+func isCancellable(status string) bool {
+	switch status {
+	case StatusPending, StatusReserved, StatusPaid:
+		return true
+	}
+	return false
+}
+
+// статус из базы в значение enum, неизвестный статус - ORDER_STATUS_UNSPECIFIED
+func statusToProto(status string) events.OrderStatus {
+	return events.OrderStatus(events.OrderStatus_value["ORDER_STATUS_"+strings.ToUpper(status)])
+}
+
+// setStatus записывает статус заказа в status_code и текстом в status для читателей без status_code
+func setStatus(order *events.Order, status string) {
+	order.StatusCode = statusToProto(status)
+	order.Status = status
+}
+
+// код причины из базы в значение enum, пустой или неизвестный код - REASON_CODE_UNSPECIFIED
+func reasonToProto(code string) events.ReasonCode {
+	if code == "" {
+		return events.ReasonCode_REASON_CODE_UNSPECIFIED
+	}
+	return events.ReasonCode(events.ReasonCode_value["REASON_CODE_"+strings.ToUpper(code)])
+}
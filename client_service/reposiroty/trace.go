@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"clients/protos"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetSagaTrace собирает историю саги заказа. Таблицы саг ведет order_service в общей базе,
+// здесь они только читаются. Сага чужого заказа не находится.
+func (r *OrderRepository) GetSagaTrace(ctx context.Context, orderID int64, userID int64) (*protos.SagaTrace, error) {
+	var trace protos.SagaTrace
+	var status, reason *string
+	var reasonCode *string
+	var createdAt, updatedAt time.Time
+
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			s.order_id,
+			s.name,
+			s.state,
+			o.status::TEXT,
+			o.reason,
+			o.reason_code,
+			s.created_at,
+			s.updated_at
+		FROM
+			saga_instances s
+			JOIN orders o ON o.order_id = s.order_id
+		WHERE
+			s.order_id = $1 AND o.user_id = $2
+	`, orderID, userID).Scan(&trace.OrderId, &trace.Name, &trace.State, &status, &reason, &reasonCode, &createdAt, &updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSagaNotFound
+		}
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+
+	if status != nil {
+		trace.StatusCode = statusToProto(*status)
+		trace.Status = *status
+	}
+	if reason != nil {
+		trace.Reason = *reason
+	}
+	if reasonCode != nil {
+		trace.ReasonCode = reasonToProto(*reasonCode)
+	}
+	trace.CreatedAt = createdAt.Format(time.RFC3339Nano)
+	trace.UpdatedAt = updatedAt.Format(time.RFC3339Nano)
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			from_state,
+			to_state,
+			topic,
+			decision,
+			compensations,
+			created_at
+		FROM
+			saga_steps
+		WHERE
+			order_id = $1
+		ORDER BY
+			id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saga steps: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var step protos.SagaTraceStep
+		var stepCreatedAt time.Time
+		if err := rows.Scan(&step.FromState, &step.ToState, &step.Topic, &step.Decision, &step.Compensations, &stepCreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saga step: %w", err)
+		}
+		step.CreatedAt = stepCreatedAt.Format(time.RFC3339Nano)
+		trace.Steps = append(trace.Steps, &step)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	trace.StatusHistory, err = r.statusHistory(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trace, nil
+}
+
+// история статусов заказа по порядку
+func (r *OrderRepository) statusHistory(ctx context.Context, orderID int64) ([]*protos.OrderStatusChange, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			COALESCE(from_status::TEXT, ''),
+			to_status::TEXT,
+			created_at
+		FROM
+			order_status_history
+		WHERE
+			order_id = $1
+		ORDER BY
+			id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*protos.OrderStatusChange
+	for rows.Next() {
+		var from, to string
+		var createdAt time.Time
+		if err := rows.Scan(&from, &to, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status history: %w", err)
+		}
+		history = append(history, &protos.OrderStatusChange{
+			FromStatus: statusToProto(from),
+			ToStatus:   statusToProto(to),
+			CreatedAt:  createdAt.Format(time.RFC3339Nano),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,288 @@
+package events
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// go test ./events -run TestSchema -update перезаписывает снимок схемы после добавления полей
+var update = flag.Bool("update", false, "update testdata/schema.json")
+
+const schemaFile = "testdata/schema.json"
+
+// снимок схемы: по нему проверяем, что уже работающие consumer'ы смогут читать новые сообщения
+type schema struct {
+	Messages map[string][]field     `json:"messages"`
+	Enums    map[string][]enumValue `json:"enums"`
+}
+
+type field struct {
+	Number      int32  `json:"number"`
+	Name        string `json:"name"`
+	JSONName    string `json:"json_name"`
+	Kind        string `json:"kind"`
+	Cardinality string `json:"cardinality"`
+	// полное имя типа для полей-сообщений и enum
+	Type string `json:"type,omitempty"`
+}
+
+type enumValue struct {
+	Number int32  `json:"number"`
+	Name   string `json:"name"`
+}
+
+func currentSchema() schema {
+	s := schema{
+		Messages: make(map[string][]field),
+		Enums:    make(map[string][]enumValue),
+	}
+
+	for _, file := range []protoreflect.FileDescriptor{File_events_messages_proto, File_events_envelope_proto} {
+		messages := file.Messages()
+		for i := 0; i < messages.Len(); i++ {
+			md := messages.Get(i)
+			fields := md.Fields()
+			for j := 0; j < fields.Len(); j++ {
+				s.Messages[string(md.FullName())] = append(s.Messages[string(md.FullName())], describeField(fields.Get(j)))
+			}
+		}
+
+		enums := file.Enums()
+		for i := 0; i < enums.Len(); i++ {
+			ed := enums.Get(i)
+			values := ed.Values()
+			for j := 0; j < values.Len(); j++ {
+				v := values.Get(j)
+				s.Enums[string(ed.FullName())] = append(s.Enums[string(ed.FullName())], enumValue{
+					Number: int32(v.Number()),
+					Name:   string(v.Name()),
+				})
+			}
+		}
+	}
+
+	return s
+}
+
+func describeField(fd protoreflect.FieldDescriptor) field {
+	f := field{
+		Number:      int32(fd.Number()),
+		Name:        string(fd.Name()),
+		JSONName:    fd.JSONName(),
+		Kind:        fd.Kind().String(),
+		Cardinality: fd.Cardinality().String(),
+	}
+	switch {
+	case fd.Message() != nil:
+		f.Type = string(fd.Message().FullName())
+	case fd.Enum() != nil:
+		f.Type = string(fd.Enum().FullName())
+	}
+	return f
+}
+
+func loadSchema(t *testing.T) schema {
+	t.Helper()
+
+	data, err := os.ReadFile(schemaFile)
+	if os.IsNotExist(err) && *update {
+		return schema{}
+	}
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", schemaFile, err)
+	}
+
+	var s schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("failed to parse %s: %v", schemaFile, err)
+	}
+	return s
+}
+
+func writeSchema(t *testing.T, s schema) {
+	t.Helper()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(schemaFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(schemaFile, append(data, '\n'), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", schemaFile, err)
+	}
+}
+
+// поля и значения enum из снимка нельзя удалять без reserved, перенумеровывать,
+// менять их тип, кардинальность и имена (по именам читает protojson)
+func TestSchemaCompatible(t *testing.T) {
+	recorded := loadSchema(t)
+	current := currentSchema()
+
+	for name, fields := range recorded.Messages {
+		desc, err := findMessage(name)
+		if err != nil {
+			t.Errorf("message %s removed: %v", name, err)
+			continue
+		}
+
+		byNumber := make(map[int32]field)
+		for _, f := range current.Messages[name] {
+			byNumber[f.Number] = f
+		}
+
+		for _, old := range fields {
+			now, ok := byNumber[old.Number]
+			if !ok {
+				if !desc.ReservedRanges().Has(protoreflect.FieldNumber(old.Number)) {
+					t.Errorf("%s.%s (= %d) removed without reserving its number", name, old.Name, old.Number)
+				}
+				continue
+			}
+			if now != old {
+				t.Errorf("%s field %d changed: was %+v, now %+v", name, old.Number, old, now)
+			}
+		}
+	}
+
+	for name, values := range recorded.Enums {
+		byNumber := make(map[int32]string)
+		for _, v := range current.Enums[name] {
+			byNumber[v.Number] = v.Name
+		}
+
+		for _, old := range values {
+			now, ok := byNumber[old.Number]
+			if !ok {
+				t.Errorf("%s value %s (= %d) removed", name, old.Name, old.Number)
+				continue
+			}
+			if now != old.Name {
+				t.Errorf("%s value %d renamed from %s to %s", name, old.Number, old.Name, now)
+			}
+		}
+	}
+}
+
+// новые поля должны попасть в снимок, иначе их изменение потом не заметит TestSchemaCompatible
+func TestSchemaRecorded(t *testing.T) {
+	current := currentSchema()
+
+	if *update {
+		writeSchema(t, current)
+		return
+	}
+
+	recorded := loadSchema(t)
+
+	var missing []string
+	for name, fields := range current.Messages {
+		known := make(map[int32]bool)
+		for _, f := range recorded.Messages[name] {
+			known[f.Number] = true
+		}
+		for _, f := range fields {
+			if !known[f.Number] {
+				missing = append(missing, fmt.Sprintf("%s.%s", name, f.Name))
+			}
+		}
+	}
+	for name, values := range current.Enums {
+		known := make(map[int32]bool)
+		for _, v := range recorded.Enums[name] {
+			known[v.Number] = true
+		}
+		for _, v := range values {
+			if !known[v.Number] {
+				missing = append(missing, fmt.Sprintf("%s.%s", name, v.Name))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		t.Errorf("schema snapshot is outdated, run go test ./events -run TestSchema -update: %v", missing)
+	}
+}
+
+func findMessage(name string) (protoreflect.MessageDescriptor, error) {
+	for _, file := range []protoreflect.FileDescriptor{File_events_messages_proto, File_events_envelope_proto} {
+		if md := file.Messages().ByName(protoreflect.FullName(name).Name()); md != nil && string(md.FullName()) == name {
+			return md, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+// заказ в формате клиентского сервиса до появления позиций и статусов
+func TestDecodeLegacyOrder(t *testing.T) {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 41)
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, 2)
+	b = protowire.AppendTag(b, 4, protowire.VarintType)
+	b = protowire.AppendVarint(b, 57)
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendString(b, "order submitted")
+
+	var order Order
+	if err := proto.Unmarshal(b, &order); err != nil {
+		t.Fatalf("failed to decode legacy order: %v", err)
+	}
+
+	if order.UserID != 41 || order.ProductSKU != 2 || order.OrderID != 57 {
+		t.Errorf("unexpected order: %v", &order)
+	}
+	if order.Status != "order submitted" {
+		t.Errorf("legacy status = %q, want text from field 5", order.Status)
+	}
+	if len(order.Items) != 0 || order.StatusCode != OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		t.Errorf("legacy order must have no items and no status code: %v", &order)
+	}
+}
+
+// ответ склада до появления products и total
+func TestDecodeLegacyOrderWithProduct(t *testing.T) {
+	var order []byte
+	order = protowire.AppendTag(order, 4, protowire.VarintType)
+	order = protowire.AppendVarint(order, 57)
+
+	var product []byte
+	product = protowire.AppendTag(product, 1, protowire.VarintType)
+	product = protowire.AppendVarint(product, 2)
+	product = protowire.AppendTag(product, 2, protowire.VarintType)
+	product = protowire.AppendVarint(product, 150)
+	product = protowire.AppendTag(product, 5, protowire.BytesType)
+	product = protowire.AppendString(product, "Золото")
+
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, order)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, product)
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, protowire.EncodeBool(true))
+
+	var msg OrderWithProduct
+	if err := proto.Unmarshal(b, &msg); err != nil {
+		t.Fatalf("failed to decode legacy message: %v", err)
+	}
+
+	if msg.GetOrder().GetOrderID() != 57 || !msg.Available {
+		t.Errorf("unexpected message: %v", &msg)
+	}
+	if msg.GetProduct().GetPrice() != 150 || msg.GetProduct().GetName() != "Золото" {
+		t.Errorf("unexpected product: %v", msg.GetProduct())
+	}
+}
@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: events/envelope.proto
+
+package events
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Конверт, в котором по kafka ходят все сообщения саги
+type Envelope struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// уникальный id сообщения
+	MessageId string `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// id саги, совпадает с id заказа
+	SagaId int64 `protobuf:"varint,2,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	// id первого сообщения в цепочке, общий для всех сообщений одной саги
+	CorrelationId string `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	// id сообщения, в ответ на которое отправлено это
+	CausationId string `protobuf:"bytes,4,opt,name=causation_id,json=causationId,proto3" json:"causation_id,omitempty"`
+	// тип события, совпадает с топиком
+	Type       string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// версия формата конверта
+	Version int32 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	// сообщение в формате, который задает type
+	Payload []byte `protobuf:"bytes,8,opt,name=payload,proto3" json:"payload,omitempty"`
+	// контекст трассировки на момент создания сообщения, нужен событиям из outbox,
+	// которые отправляются позже вне исходного запроса
+	TraceContext map[string]string `protobuf:"bytes,9,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// команда повторно отправлена оператором: участник выполняет ее и отвечает заново,
+	// даже если исходную команду уже обработал, потому что ответ мог потеряться
+	Retry         bool `protobuf:"varint,10,opt,name=retry,proto3" json:"retry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	mi := &file_events_envelope_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_events_envelope_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_events_envelope_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Envelope) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *Envelope) GetSagaId() int64 {
+	if x != nil {
+		return x.SagaId
+	}
+	return 0
+}
+
+func (x *Envelope) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *Envelope) GetCausationId() string {
+	if x != nil {
+		return x.CausationId
+	}
+	return ""
+}
+
+func (x *Envelope) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Envelope) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *Envelope) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Envelope) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Envelope) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
+func (x *Envelope) GetRetry() bool {
+	if x != nil {
+		return x.Retry
+	}
+	return false
+}
+
+var File_events_envelope_proto protoreflect.FileDescriptor
+
+const file_events_envelope_proto_rawDesc = "" +
+	"\n" +
+	"\x15events/envelope.proto\x12\x06events\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb1\x03\n" +
+	"\bEnvelope\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
+	"\asaga_id\x18\x02 \x01(\x03R\x06sagaId\x12%\n" +
+	"\x0ecorrelation_id\x18\x03 \x01(\tR\rcorrelationId\x12!\n" +
+	"\fcausation_id\x18\x04 \x01(\tR\vcausationId\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12;\n" +
+	"\voccurred_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x18\n" +
+	"\aversion\x18\a \x01(\x05R\aversion\x12\x18\n" +
+	"\apayload\x18\b \x01(\fR\apayload\x12G\n" +
+	"\rtrace_context\x18\t \x03(\v2\".events.Envelope.TraceContextEntryR\ftraceContext\x12\x14\n" +
+	"\x05retry\x18\n" +
+	" \x01(\bR\x05retry\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B;Z9github.com/Iowel/app-saga-service/contracts/events;eventsb\x06proto3"
+
+var (
+	file_events_envelope_proto_rawDescOnce sync.Once
+	file_events_envelope_proto_rawDescData []byte
+)
+
+func file_events_envelope_proto_rawDescGZIP() []byte {
+	file_events_envelope_proto_rawDescOnce.Do(func() {
+		file_events_envelope_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_envelope_proto_rawDesc), len(file_events_envelope_proto_rawDesc)))
+	})
+	return file_events_envelope_proto_rawDescData
+}
+
+var file_events_envelope_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_events_envelope_proto_goTypes = []any{
+	(*Envelope)(nil),              // 0: events.Envelope
+	nil,                           // 1: events.Envelope.TraceContextEntry
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_events_envelope_proto_depIdxs = []int32{
+	2, // 0: events.Envelope.occurred_at:type_name -> google.protobuf.Timestamp
+	1, // 1: events.Envelope.trace_context:type_name -> events.Envelope.TraceContextEntry
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_events_envelope_proto_init() }
+func file_events_envelope_proto_init() {
+	if File_events_envelope_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_envelope_proto_rawDesc), len(file_events_envelope_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_envelope_proto_goTypes,
+		DependencyIndexes: file_events_envelope_proto_depIdxs,
+		MessageInfos:      file_events_envelope_proto_msgTypes,
+	}.Build()
+	File_events_envelope_proto = out.File
+	file_events_envelope_proto_goTypes = nil
+	file_events_envelope_proto_depIdxs = nil
+}
@@ -0,0 +1,606 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: events/messages.proto
+
+// Сообщения, которыми сервисы обмениваются через kafka.
+// Номера и типы полей менять нельзя: их проверяют тесты совместимости в compat_test.go
+
+package events
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Код причины, с которой заказ завершен или отменен
+type ReasonCode int32
+
+const (
+	ReasonCode_REASON_CODE_UNSPECIFIED        ReasonCode = 0
+	ReasonCode_REASON_CODE_COMPLETED          ReasonCode = 1
+	ReasonCode_REASON_CODE_OUT_OF_STOCK       ReasonCode = 2
+	ReasonCode_REASON_CODE_INSUFFICIENT_FUNDS ReasonCode = 3
+	// не удалось выдать покупку после оплаты
+	ReasonCode_REASON_CODE_COMMIT_FAILED ReasonCode = 4
+	// участник саги не ответил вовремя
+	ReasonCode_REASON_CODE_TIMEOUT               ReasonCode = 5
+	ReasonCode_REASON_CODE_CANCELLED_BY_USER     ReasonCode = 6
+	ReasonCode_REASON_CODE_CANCELLED_BY_OPERATOR ReasonCode = 7
+)
+
+// Enum value maps for ReasonCode.
+var (
+	ReasonCode_name = map[int32]string{
+		0: "REASON_CODE_UNSPECIFIED",
+		1: "REASON_CODE_COMPLETED",
+		2: "REASON_CODE_OUT_OF_STOCK",
+		3: "REASON_CODE_INSUFFICIENT_FUNDS",
+		4: "REASON_CODE_COMMIT_FAILED",
+		5: "REASON_CODE_TIMEOUT",
+		6: "REASON_CODE_CANCELLED_BY_USER",
+		7: "REASON_CODE_CANCELLED_BY_OPERATOR",
+	}
+	ReasonCode_value = map[string]int32{
+		"REASON_CODE_UNSPECIFIED":           0,
+		"REASON_CODE_COMPLETED":             1,
+		"REASON_CODE_OUT_OF_STOCK":          2,
+		"REASON_CODE_INSUFFICIENT_FUNDS":    3,
+		"REASON_CODE_COMMIT_FAILED":         4,
+		"REASON_CODE_TIMEOUT":               5,
+		"REASON_CODE_CANCELLED_BY_USER":     6,
+		"REASON_CODE_CANCELLED_BY_OPERATOR": 7,
+	}
+)
+
+func (x ReasonCode) Enum() *ReasonCode {
+	p := new(ReasonCode)
+	*p = x
+	return p
+}
+
+func (x ReasonCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReasonCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_events_messages_proto_enumTypes[0].Descriptor()
+}
+
+func (ReasonCode) Type() protoreflect.EnumType {
+	return &file_events_messages_proto_enumTypes[0]
+}
+
+func (x ReasonCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReasonCode.Descriptor instead.
+func (ReasonCode) EnumDescriptor() ([]byte, []int) {
+	return file_events_messages_proto_rawDescGZIP(), []int{0}
+}
+
+// Статус заказа, переходы между статусами проверяет order_service
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	// заказ создан, сага запущена
+	OrderStatus_ORDER_STATUS_PENDING OrderStatus = 1
+	// товар зарезервирован
+	OrderStatus_ORDER_STATUS_RESERVED OrderStatus = 2
+	// деньги заблокированы на кошельке
+	OrderStatus_ORDER_STATUS_PAID      OrderStatus = 3
+	OrderStatus_ORDER_STATUS_COMPLETED OrderStatus = 4
+	OrderStatus_ORDER_STATUS_CANCELLED OrderStatus = 5
+	// заказ отменен после оплаты, деньги возвращены
+	OrderStatus_ORDER_STATUS_REFUNDED OrderStatus = 6
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_PENDING",
+		2: "ORDER_STATUS_RESERVED",
+		3: "ORDER_STATUS_PAID",
+		4: "ORDER_STATUS_COMPLETED",
+		5: "ORDER_STATUS_CANCELLED",
+		6: "ORDER_STATUS_REFUNDED",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_PENDING":     1,
+		"ORDER_STATUS_RESERVED":    2,
+		"ORDER_STATUS_PAID":        3,
+		"ORDER_STATUS_COMPLETED":   4,
+		"ORDER_STATUS_CANCELLED":   5,
+		"ORDER_STATUS_REFUNDED":    6,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_events_messages_proto_enumTypes[1].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_events_messages_proto_enumTypes[1]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_events_messages_proto_rawDescGZIP(), []int{1}
+}
+
+// Сообщение для заказа
+type Order struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	UserID     int64                  `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Timestamp  int64                  `protobuf:"varint,2,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	ProductSKU int64                  `protobuf:"varint,3,opt,name=ProductSKU,proto3" json:"ProductSKU,omitempty"`
+	OrderID    int64                  `protobuf:"varint,4,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	// текст статуса для читателей без status_code, пишется вместе с ним.
+	// Когда поле уберут, номер 5 нужно оставить в reserved
+	//
+	// Deprecated: Marked as deprecated in events/messages.proto.
+	Status string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Reason string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	// позиции заказа, ProductSKU оставлен для заказов из одного товара
+	Items []*OrderItem `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
+	// код причины текущего статуса, reason - его текст на языке клиента
+	ReasonCode ReasonCode `protobuf:"varint,8,opt,name=reason_code,json=reasonCode,proto3,enum=events.ReasonCode" json:"reason_code,omitempty"`
+	// статус заказа
+	StatusCode    OrderStatus `protobuf:"varint,9,opt,name=status_code,json=statusCode,proto3,enum=events.OrderStatus" json:"status_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_events_messages_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_events_messages_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_events_messages_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Order) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *Order) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Order) GetProductSKU() int64 {
+	if x != nil {
+		return x.ProductSKU
+	}
+	return 0
+}
+
+func (x *Order) GetOrderID() int64 {
+	if x != nil {
+		return x.OrderID
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in events/messages.proto.
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Order) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetReasonCode() ReasonCode {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return ReasonCode_REASON_CODE_UNSPECIFIED
+}
+
+func (x *Order) GetStatusCode() OrderStatus {
+	if x != nil {
+		return x.StatusCode
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+// Позиция заказа
+type OrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           int64                  `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_events_messages_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_events_messages_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_events_messages_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderItem) GetSku() int64 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *OrderItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// Сообщение для продукта в заказе
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           int64                  `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Price         int64                  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	Cnt           int64                  `protobuf:"varint,3,opt,name=cnt,proto3" json:"cnt,omitempty"`
+	Avatar        string                 `protobuf:"bytes,4,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_events_messages_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_events_messages_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_events_messages_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Product) GetSku() int64 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *Product) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Product) GetCnt() int64 {
+	if x != nil {
+		return x.Cnt
+	}
+	return 0
+}
+
+func (x *Product) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type OrderWithProduct struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Order             *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Product           *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	Available         bool                   `protobuf:"varint,3,opt,name=Available,proto3" json:"Available,omitempty"`
+	BalanceSufficient bool                   `protobuf:"varint,4,opt,name=balanceSufficient,proto3" json:"balanceSufficient,omitempty"`
+	// продукты по всем позициям заказа в порядке items
+	Products []*Product `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
+	// итоговая сумма заказа с учетом количества
+	Total         int64 `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderWithProduct) Reset() {
+	*x = OrderWithProduct{}
+	mi := &file_events_messages_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderWithProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderWithProduct) ProtoMessage() {}
+
+func (x *OrderWithProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_events_messages_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderWithProduct.ProtoReflect.Descriptor instead.
+func (*OrderWithProduct) Descriptor() ([]byte, []int) {
+	return file_events_messages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderWithProduct) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderWithProduct) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *OrderWithProduct) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *OrderWithProduct) GetBalanceSufficient() bool {
+	if x != nil {
+		return x.BalanceSufficient
+	}
+	return false
+}
+
+func (x *OrderWithProduct) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *OrderWithProduct) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_events_messages_proto protoreflect.FileDescriptor
+
+const file_events_messages_proto_rawDesc = "" +
+	"\n" +
+	"\x15events/messages.proto\x12\x06events\"\xbf\x02\n" +
+	"\x05Order\x12\x16\n" +
+	"\x06UserID\x18\x01 \x01(\x03R\x06UserID\x12\x1c\n" +
+	"\tTimestamp\x18\x02 \x01(\x03R\tTimestamp\x12\x1e\n" +
+	"\n" +
+	"ProductSKU\x18\x03 \x01(\x03R\n" +
+	"ProductSKU\x12\x18\n" +
+	"\aOrderID\x18\x04 \x01(\x03R\aOrderID\x12\x1a\n" +
+	"\x06status\x18\x05 \x01(\tB\x02\x18\x01R\x06status\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12'\n" +
+	"\x05items\x18\a \x03(\v2\x11.events.OrderItemR\x05items\x123\n" +
+	"\vreason_code\x18\b \x01(\x0e2\x12.events.ReasonCodeR\n" +
+	"reasonCode\x124\n" +
+	"\vstatus_code\x18\t \x01(\x0e2\x13.events.OrderStatusR\n" +
+	"statusCode\"9\n" +
+	"\tOrderItem\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\x03R\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"o\n" +
+	"\aProduct\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\x03R\x03sku\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x03R\x05price\x12\x10\n" +
+	"\x03cnt\x18\x03 \x01(\x03R\x03cnt\x12\x16\n" +
+	"\x06avatar\x18\x04 \x01(\tR\x06avatar\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\"\xf1\x01\n" +
+	"\x10OrderWithProduct\x12#\n" +
+	"\x05order\x18\x01 \x01(\v2\r.events.OrderR\x05order\x12)\n" +
+	"\aproduct\x18\x02 \x01(\v2\x0f.events.ProductR\aproduct\x12\x1c\n" +
+	"\tAvailable\x18\x03 \x01(\bR\tAvailable\x12,\n" +
+	"\x11balanceSufficient\x18\x04 \x01(\bR\x11balanceSufficient\x12+\n" +
+	"\bproducts\x18\x05 \x03(\v2\x0f.events.ProductR\bproducts\x12\x14\n" +
+	"\x05total\x18\x06 \x01(\x03R\x05total*\x88\x02\n" +
+	"\n" +
+	"ReasonCode\x12\x1b\n" +
+	"\x17REASON_CODE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15REASON_CODE_COMPLETED\x10\x01\x12\x1c\n" +
+	"\x18REASON_CODE_OUT_OF_STOCK\x10\x02\x12\"\n" +
+	"\x1eREASON_CODE_INSUFFICIENT_FUNDS\x10\x03\x12\x1d\n" +
+	"\x19REASON_CODE_COMMIT_FAILED\x10\x04\x12\x17\n" +
+	"\x13REASON_CODE_TIMEOUT\x10\x05\x12!\n" +
+	"\x1dREASON_CODE_CANCELLED_BY_USER\x10\x06\x12%\n" +
+	"!REASON_CODE_CANCELLED_BY_OPERATOR\x10\a*\xca\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ORDER_STATUS_PENDING\x10\x01\x12\x19\n" +
+	"\x15ORDER_STATUS_RESERVED\x10\x02\x12\x15\n" +
+	"\x11ORDER_STATUS_PAID\x10\x03\x12\x1a\n" +
+	"\x16ORDER_STATUS_COMPLETED\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x05\x12\x19\n" +
+	"\x15ORDER_STATUS_REFUNDED\x10\x06B;Z9github.com/Iowel/app-saga-service/contracts/events;eventsb\x06proto3"
+
+var (
+	file_events_messages_proto_rawDescOnce sync.Once
+	file_events_messages_proto_rawDescData []byte
+)
+
+func file_events_messages_proto_rawDescGZIP() []byte {
+	file_events_messages_proto_rawDescOnce.Do(func() {
+		file_events_messages_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_messages_proto_rawDesc), len(file_events_messages_proto_rawDesc)))
+	})
+	return file_events_messages_proto_rawDescData
+}
+
+var file_events_messages_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_events_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_events_messages_proto_goTypes = []any{
+	(ReasonCode)(0),          // 0: events.ReasonCode
+	(OrderStatus)(0),         // 1: events.OrderStatus
+	(*Order)(nil),            // 2: events.Order
+	(*OrderItem)(nil),        // 3: events.OrderItem
+	(*Product)(nil),          // 4: events.Product
+	(*OrderWithProduct)(nil), // 5: events.OrderWithProduct
+}
+var file_events_messages_proto_depIdxs = []int32{
+	3, // 0: events.Order.items:type_name -> events.OrderItem
+	0, // 1: events.Order.reason_code:type_name -> events.ReasonCode
+	1, // 2: events.Order.status_code:type_name -> events.OrderStatus
+	2, // 3: events.OrderWithProduct.order:type_name -> events.Order
+	4, // 4: events.OrderWithProduct.product:type_name -> events.Product
+	4, // 5: events.OrderWithProduct.products:type_name -> events.Product
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_events_messages_proto_init() }
+func file_events_messages_proto_init() {
+	if File_events_messages_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_messages_proto_rawDesc), len(file_events_messages_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_messages_proto_goTypes,
+		DependencyIndexes: file_events_messages_proto_depIdxs,
+		EnumInfos:         file_events_messages_proto_enumTypes,
+		MessageInfos:      file_events_messages_proto_msgTypes,
+	}.Build()
+	File_events_messages_proto = out.File
+	file_events_messages_proto_goTypes = nil
+	file_events_messages_proto_depIdxs = nil
+}
@@ -0,0 +1,225 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// сколько ждем одну проверку
+const checkTimeout = 2 * time.Second
+
+// статусы в отчете
+const (
+	StatusOK       = "ok"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// ErrNotReady - зависимость еще не подключена
+var ErrNotReady = errors.New("not ready")
+
+// Check проверяет одну зависимость сервиса
+type Check func(ctx context.Context) error
+
+type component struct {
+	name     string
+	check    Check
+	optional bool
+}
+
+// ComponentStatus - результат проверки одной зависимости
+type ComponentStatus struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Report - состояние сервиса и всех его зависимостей
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Ready сообщает, что все обязательные зависимости доступны
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Checker собирает проверки зависимостей и отдает их через /healthz, /readyz
+// и стандартный grpc health сервис
+type Checker struct {
+	mu         sync.Mutex
+	components []component
+	grpc       *grpchealth.Server
+}
+
+func New() *Checker {
+	server := grpchealth.NewServer()
+	// пока проверки не выполнялись, сервис не готов
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return &Checker{grpc: server}
+}
+
+// Add добавляет зависимость, без которой сервис не готов обрабатывать запросы
+func (c *Checker) Add(name string, check Check) {
+	c.add(component{name: name, check: check})
+}
+
+// AddOptional добавляет зависимость, которая есть в отчете, но не влияет на готовность
+func (c *Checker) AddOptional(name string, check Check) {
+	c.add(component{name: name, check: check, optional: true})
+}
+
+func (c *Checker) add(comp component) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.components = append(c.components, comp)
+	c.grpc.SetServingStatus(comp.name, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// RegisterGRPC регистрирует grpc.health.v1.Health на server
+func (c *Checker) RegisterGRPC(server *grpc.Server) {
+	healthpb.RegisterHealthServer(server, c.grpc)
+}
+
+// Routes добавляет /healthz - процесс жив, и /readyz - сервис готов, оба с отчетом по зависимостям
+func (c *Checker) Routes(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, c.Check(r.Context()))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := c.Check(r.Context())
+		code := http.StatusOK
+		if !report.Ready() {
+			code = http.StatusServiceUnavailable
+		}
+		writeReport(w, code, report)
+	})
+}
+
+func writeReport(w http.ResponseWriter, code int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// Check выполняет все проверки параллельно и обновляет статус grpc health
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	components := append([]component(nil), c.components...)
+	c.mu.Unlock()
+
+	results := make([]ComponentStatus, len(components))
+	var wg sync.WaitGroup
+	for i, comp := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			results[i] = ComponentStatus{Status: StatusOK, Optional: comp.optional}
+			if err := comp.check(ctx); err != nil {
+				results[i].Status = StatusDown
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Components: make(map[string]ComponentStatus, len(components))}
+	for i, comp := range components {
+		report.Components[comp.name] = results[i]
+		c.grpc.SetServingStatus(comp.name, servingStatus(results[i].Status == StatusOK))
+		if results[i].Status != StatusOK && !comp.optional {
+			report.Status = StatusDegraded
+		}
+	}
+	c.grpc.SetServingStatus("", servingStatus(report.Ready()))
+
+	return report
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+// Run периодически выполняет проверки, чтобы grpc Watch получал изменения без запросов к /readyz,
+// и пишет в лог смену готовности
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ready := false
+	for {
+		report := c.Check(ctx)
+		if report.Ready() != ready {
+			ready = report.Ready()
+			slog.InfoContext(ctx, "service readiness changed", "ready", ready, "components", report.Components)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status - состояние зависимости, которое выставляет сам сервис, например после подключения.
+// Нулевое значение не готово
+type Status struct {
+	mu    sync.Mutex
+	ready bool
+	err   error
+}
+
+// Set отмечает зависимость готовой (err == nil) или неготовой с причиной err
+func (s *Status) Set(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ready = err == nil
+	s.err = err
+}
+
+// Check возвращает ошибку, пока зависимость не готова
+func (s *Status) Check(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ready {
+		return nil
+	}
+	if s.err != nil {
+		return s.err
+	}
+	return ErrNotReady
+}
+
+// All объединяет проверки одной зависимости, например подключение и миграции
+func All(checks ...Check) Check {
+	return func(ctx context.Context) error {
+		for _, check := range checks {
+			if err := check(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
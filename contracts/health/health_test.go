@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func get(t *testing.T, mux *http.ServeMux, path string) (int, Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("unmarshal %s: %v", rec.Body.String(), err)
+	}
+	return rec.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	var postgres Status
+	checker := New()
+	checker.Add("postgres", postgres.Check)
+	checker.AddOptional("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	mux := http.NewServeMux()
+	checker.Routes(mux)
+
+	// сервис жив, но база еще не подключена
+	if code, _ := get(t, mux, "/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz = %d, want 200", code)
+	}
+	code, report := get(t, mux, "/readyz")
+	if code != http.StatusServiceUnavailable || report.Status != StatusDegraded {
+		t.Errorf("/readyz = %d %s, want 503 degraded", code, report.Status)
+	}
+	if report.Components["postgres"].Error != ErrNotReady.Error() {
+		t.Errorf("postgres = %+v, want %q", report.Components["postgres"], ErrNotReady)
+	}
+
+	// необязательный redis не мешает готовности
+	postgres.Set(nil)
+	code, report = get(t, mux, "/readyz")
+	if code != http.StatusOK || report.Status != StatusOK {
+		t.Errorf("/readyz = %d %s, want 200 ok", code, report.Status)
+	}
+	if redis := report.Components["redis"]; redis.Status != StatusDown || !redis.Optional {
+		t.Errorf("redis = %+v, want optional down", redis)
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	var kafka Status
+	checker := New()
+	checker.Add("kafka_producer", kafka.Check)
+
+	status := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := checker.grpc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("check %q: %v", service, err)
+		}
+		return resp.Status
+	}
+
+	checker.Check(context.Background())
+	if got := status(""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("service status = %s, want NOT_SERVING", got)
+	}
+
+	kafka.Set(nil)
+	checker.Check(context.Background())
+	if got := status(""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("service status = %s, want SERVING", got)
+	}
+	if got := status("kafka_producer"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("kafka_producer status = %s, want SERVING", got)
+	}
+}
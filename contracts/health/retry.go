@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// границы паузы между попытками подключения
+const (
+	retryInitialBackoff = time.Second
+	retryMaxBackoff     = 30 * time.Second
+)
+
+// Retry повторяет fn с растущей паузой, пока она не выполнится без ошибки
+// или не закончится ctx. Каждая неудача выставляется в status, если он задан
+func Retry(ctx context.Context, name string, status *Status, fn func(ctx context.Context) error) error {
+	backoff := retryInitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if status != nil {
+				status.Set(nil)
+			}
+			slog.InfoContext(ctx, "dependency ready", "dependency", name, "attempts", attempt)
+			return nil
+		}
+		if status != nil {
+			status.Set(err)
+		}
+		slog.WarnContext(ctx, "dependency not ready, retrying", "dependency", name, "attempt", attempt, "backoff", backoff, "error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > retryMaxBackoff {
+			backoff = retryMaxBackoff
+		}
+	}
+}
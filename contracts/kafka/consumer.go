@@ -0,0 +1,264 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type consumeFunction func(ctx context.Context, message *sarama.ConsumerMessage) error
+
+// Option настраивает повторы и dead-letter очередь consumer'a
+type Option func(*options)
+
+type options struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	dlqProducer    sarama.SyncProducer
+	status         *GroupStatus
+}
+
+// по умолчанию 3 повтора с паузой 100ms, 200ms, 400ms
+func defaultOptions() options {
+	return options{
+		maxRetries:     3,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     5 * time.Second,
+	}
+}
+
+// WithRetries задает число повторов обработки и границы экспоненциальной паузы между ними
+func WithRetries(maxRetries int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.maxRetries = maxRetries
+		o.initialBackoff = initialBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithDeadLetterProducer задает producer для отправки сообщений в <topic>.dlq,
+// без него consumer создает свой
+func WithDeadLetterProducer(producer sarama.SyncProducer) Option {
+	return func(o *options) {
+		o.dlqProducer = producer
+	}
+}
+
+type consumer struct {
+	fn   consumeFunction
+	opts options
+}
+
+// действия которые выполняются при запуске consumer'a
+func (consumer *consumer) Setup(sarama.ConsumerGroupSession) error {
+	consumer.opts.status.setJoined(true)
+	return nil
+}
+
+// действия которые выполняются при остановке consumer'a
+func (consumer *consumer) Cleanup(sarama.ConsumerGroupSession) error {
+	consumer.opts.status.setJoined(false)
+	return nil
+}
+
+// потребление сообщений
+func (consumer *consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	// по очереди обрабатываем сообщения, пока сессия не закончится
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			// при остановке новые сообщения не берем, их получит следующий владелец партиции
+			if session.Context().Err() != nil {
+				return nil
+			}
+			if !consumer.process(session, message) {
+				return nil
+			}
+		}
+	}
+}
+
+// обрабатываем одно сообщение, false - если сессия закончилась раньше, чем его удалось пометить
+func (consumer *consumer) process(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) bool {
+	ctx, span := startConsumerSpan(message)
+	// обработчик получает payload из конверта, а сам конверт - через контекст
+	ctx, unwrapped := unwrapMessage(ctx, message)
+	attempts, done, err := consumer.handle(session.Context(), ctx, unwrapped)
+	span.SetAttributes(attribute.Int("messaging.kafka.attempts", attempts))
+	endSpan(span, err)
+
+	// сессия закончилась во время повторов, сообщение получит следующий владелец партиции
+	if !done {
+		return false
+	}
+	messagesConsumed.WithLabelValues(message.Topic, result(err)).Inc()
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to handle message", "attempts", attempts, "error", err)
+
+		// сообщение помечаем обработанным только после того, как оно сохранено в dlq
+		if !consumer.deadLetter(session.Context(), ctx, message, err, attempts) {
+			return false
+		}
+	}
+
+	session.MarkMessage(message, "")
+	return true
+}
+
+// обрабатываем сообщение с повторами, возвращаем число попыток и последнюю ошибку.
+// done = false, если сессия закончилась раньше, чем попытки. Обработчик получает handlerCtx,
+// который не отменяется вместе с сессией
+func (consumer *consumer) handle(ctx context.Context, handlerCtx context.Context, message *sarama.ConsumerMessage) (attempts int, done bool, err error) {
+	backoff := consumer.opts.initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err = consumer.fn(handlerCtx, message)
+		if err != nil {
+			handlerErrors.WithLabelValues(message.Topic).Inc()
+		}
+		if err == nil || attempt > consumer.opts.maxRetries {
+			return attempt, true, err
+		}
+
+		slog.WarnContext(handlerCtx, "retrying message", "attempt", attempt, "backoff", backoff, "error", err)
+		if !sleep(ctx, backoff) {
+			return attempt, false, err
+		}
+		backoff = nextBackoff(backoff, consumer.opts.maxBackoff)
+	}
+}
+
+// отправляем сообщение в dlq, пока не получится или не закончится сессия
+func (consumer *consumer) deadLetter(ctx context.Context, logCtx context.Context, message *sarama.ConsumerMessage, cause error, attempts int) bool {
+	backoff := consumer.opts.initialBackoff
+
+	for {
+		err := SendDeadLetter(consumer.opts.dlqProducer, message, cause, attempts)
+		if err == nil {
+			deadLetters.WithLabelValues(message.Topic).Inc()
+			slog.WarnContext(logCtx, "message moved to dead-letter topic", "dlq_topic", DeadLetterTopic(message.Topic))
+			return true
+		}
+
+		slog.ErrorContext(logCtx, "failed to send message to dead-letter topic", "dlq_topic", DeadLetterTopic(message.Topic), "error", err)
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff = nextBackoff(backoff, consumer.opts.maxBackoff)
+	}
+}
+
+func nextBackoff(backoff, maxBackoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// ждем d, false - если контекст завершился раньше. Тесты подменяют паузу, чтобы проверить ее длительность
+var sleep = func(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Group - запущенная подписка consumer группы
+type Group struct {
+	done chan struct{}
+}
+
+// Wait ждет остановки подписки: после отмены контекста StartConsuming дожидается
+// текущих обработчиков, фиксирует offset'ы и закрывает группу
+func (g *Group) Wait() {
+	<-g.done
+}
+
+// StartConsuming подписывает группу на topic и обрабатывает сообщения в фоне,
+// пока не будет отменен ctx
+func StartConsuming(ctx context.Context, brokers []string, topic string, group string, consumeFunction consumeFunction, opts ...Option) (*Group, error) {
+	config := sarama.NewConfig()
+
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	consumer := consumer{
+		fn:   consumeFunction,
+		opts: defaultOptions(),
+	}
+	for _, opt := range opts {
+		opt(&consumer.opts)
+	}
+
+	// без внешнего producer'a для dlq создаем свой и закрываем его вместе с группой
+	var ownProducer sarama.SyncProducer
+	if consumer.opts.dlqProducer == nil {
+		producer, err := NewSyncProducer(brokers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dlq producer: %w", err)
+		}
+		consumer.opts.dlqProducer = producer
+		ownProducer = producer
+	}
+
+	// создаем consumer группу
+	consumerGroup, err := sarama.NewConsumerGroup(brokers, group, config)
+
+	if err != nil {
+		if ownProducer != nil {
+			_ = ownProducer.Close()
+		}
+		return nil, err
+	}
+
+	g := &Group{done: make(chan struct{})}
+
+	go func() {
+		defer close(g.done)
+
+		for {
+			if err := consumerGroup.Consume(ctx, []string{topic}, &consumer); err != nil {
+				slog.Error("consumer group failed", "topic", topic, "group", group, "error", err)
+				consumer.opts.status.setError(err)
+
+				// кластер недоступен, ждем перед новой попыткой подписаться
+				if !sleep(ctx, consumer.opts.initialBackoff) {
+					break
+				}
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		// Consume вернулся после завершения текущих обработчиков, выходим из группы
+		if err := consumerGroup.Close(); err != nil {
+			slog.Error("failed to close consumer group", "topic", topic, "group", group, "error", err)
+		}
+		if ownProducer != nil {
+			if err := ownProducer.Close(); err != nil {
+				slog.Error("failed to close dlq producer", "topic", topic, "group", group, "error", err)
+			}
+		}
+		slog.Info("consumer group stopped", "topic", topic, "group", group)
+	}()
+
+	return g, nil
+}
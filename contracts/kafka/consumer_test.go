@@ -0,0 +1,280 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+)
+
+// остановка во время обработки: текущее сообщение доходит до конца и помечается,
+// следующие остаются для нового владельца партиции
+func TestConsumeClaimDrainsOnShutdown(t *testing.T) {
+	sessionCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var handled []int64
+	c := consumer{
+		fn: func(ctx context.Context, message *sarama.ConsumerMessage) error {
+			cancel()
+			if ctx.Err() != nil {
+				t.Errorf("handler context canceled with session: %v", ctx.Err())
+			}
+			handled = append(handled, message.Offset)
+			return nil
+		},
+		opts: defaultOptions(),
+	}
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "orders", Offset: 1}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "orders", Offset: 2}
+
+	session := &fakeSession{ctx: sessionCtx}
+	if err := c.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("consume claim: %v", err)
+	}
+
+	if len(handled) != 1 || handled[0] != 1 {
+		t.Fatalf("handled offsets %v, want [1]", handled)
+	}
+	if len(session.marked) != 1 || session.marked[0].Offset != 1 {
+		t.Fatalf("marked %d messages, want offset 1 only", len(session.marked))
+	}
+}
+
+// подменяем паузу между попытками: записываем ее длительность и не ждем.
+// После cancelAfter пауз отменяем контекст сессии, как при остановке сервиса
+func fakeSleep(t *testing.T, cancel context.CancelFunc, cancelAfter int) *[]time.Duration {
+	t.Helper()
+
+	var sleeps []time.Duration
+	original := sleep
+	sleep = func(ctx context.Context, d time.Duration) bool {
+		sleeps = append(sleeps, d)
+		if cancelAfter > 0 && len(sleeps) >= cancelAfter {
+			cancel()
+		}
+		return ctx.Err() == nil
+	}
+	t.Cleanup(func() { sleep = original })
+	return &sleeps
+}
+
+func TestConsumeClaimRetries(t *testing.T) {
+	errHandler := errors.New("wallet unavailable")
+	errBroker := errors.New("broker unavailable")
+
+	tests := []struct {
+		name string
+		// сколько первых попыток обработчик завершает ошибкой
+		failures int
+		// результаты отправок в dlq по порядку, nil - успех
+		dlqSends     []error
+		wantAttempts int
+		wantSleeps   []time.Duration
+	}{
+		{
+			name:         "success on first attempt",
+			wantAttempts: 1,
+		},
+		{
+			name:         "success after retries",
+			failures:     2,
+			wantAttempts: 3,
+			wantSleeps:   []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:         "retries exhausted, backoff capped",
+			failures:     10,
+			dlqSends:     []error{nil},
+			wantAttempts: 4,
+			wantSleeps:   []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond},
+		},
+		{
+			name:         "dead letter send retried until it succeeds",
+			failures:     10,
+			dlqSends:     []error{errBroker, errBroker, nil},
+			wantAttempts: 4,
+			wantSleeps: []time.Duration{
+				10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond,
+				10 * time.Millisecond, 20 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sleeps := fakeSleep(t, cancel, 0)
+			session := &fakeSession{ctx: ctx}
+
+			producer := mocks.NewSyncProducer(t, nil)
+			defer producer.Close()
+			var dlq []*sarama.ProducerMessage
+			for _, result := range tt.dlqSends {
+				check := func(msg *sarama.ProducerMessage) error {
+					if len(session.marked) != 0 {
+						t.Errorf("message marked before it was saved to dlq")
+					}
+					dlq = append(dlq, msg)
+					return nil
+				}
+				if result == nil {
+					producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(check)
+				} else {
+					producer.ExpectSendMessageWithMessageCheckerFunctionAndFail(check, result)
+				}
+			}
+
+			attempts := 0
+			c := consumer{
+				fn: func(ctx context.Context, message *sarama.ConsumerMessage) error {
+					attempts++
+					if attempts <= tt.failures {
+						return errHandler
+					}
+					return nil
+				},
+				opts: defaultOptions(),
+			}
+			WithRetries(3, 10*time.Millisecond, 25*time.Millisecond)(&c.opts)
+			WithDeadLetterProducer(producer)(&c.opts)
+
+			message := &sarama.ConsumerMessage{
+				Topic:     "check_balance",
+				Partition: 2,
+				Offset:    7,
+				Key:       []byte("57"),
+				Value:     []byte("payload"),
+				Headers:   []*sarama.RecordHeader{{Key: []byte("x-request-id"), Value: []byte("abc")}},
+			}
+			claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 1)}
+			claim.messages <- message
+			close(claim.messages)
+
+			if err := c.ConsumeClaim(session, claim); err != nil {
+				t.Fatalf("consume claim: %v", err)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if !slices.Equal(*sleeps, tt.wantSleeps) {
+				t.Errorf("sleeps = %v, want %v", *sleeps, tt.wantSleeps)
+			}
+			if len(session.marked) != 1 || session.marked[0] != message {
+				t.Fatalf("marked %d messages, want the consumed one", len(session.marked))
+			}
+			if len(dlq) != len(tt.dlqSends) {
+				t.Fatalf("dlq sends = %d, want %d", len(dlq), len(tt.dlqSends))
+			}
+			if len(dlq) == 0 {
+				return
+			}
+
+			sent := dlq[len(dlq)-1]
+			if sent.Topic != "check_balance.dlq" {
+				t.Errorf("dlq topic = %s, want check_balance.dlq", sent.Topic)
+			}
+			if key, _ := sent.Key.Encode(); string(key) != "57" {
+				t.Errorf("dlq key = %s, want 57", key)
+			}
+			if value, _ := sent.Value.Encode(); string(value) != "payload" {
+				t.Errorf("dlq value = %s, want payload", value)
+			}
+
+			headers := make(map[string]string)
+			for _, h := range sent.Headers {
+				headers[string(h.Key)] = string(h.Value)
+			}
+			want := map[string]string{
+				"x-request-id":          "abc",
+				HeaderOriginalTopic:     "check_balance",
+				HeaderOriginalPartition: "2",
+				HeaderOriginalOffset:    "7",
+				HeaderError:             errHandler.Error(),
+				HeaderAttempts:          "4",
+			}
+			for key, value := range want {
+				if headers[key] != value {
+					t.Errorf("header %s = %q, want %q", key, headers[key], value)
+				}
+			}
+			if _, err := time.Parse(time.RFC3339, headers[HeaderFailedAt]); err != nil {
+				t.Errorf("header %s = %q is not RFC 3339", HeaderFailedAt, headers[HeaderFailedAt])
+			}
+		})
+	}
+}
+
+// при остановке во время повторов или отправки в dlq сообщение не помечается,
+// его получит следующий владелец партиции
+func TestConsumeClaimStopsOnCancel(t *testing.T) {
+	tests := []struct {
+		name         string
+		cancelAfter  int
+		dlqSends     int
+		wantAttempts int
+	}{
+		{name: "during retries", cancelAfter: 2, wantAttempts: 2},
+		{name: "during dead letter send", cancelAfter: 4, dlqSends: 1, wantAttempts: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			fakeSleep(t, cancel, tt.cancelAfter)
+
+			producer := mocks.NewSyncProducer(t, nil)
+			defer producer.Close()
+			for range tt.dlqSends {
+				producer.ExpectSendMessageAndFail(errors.New("broker unavailable"))
+			}
+
+			attempts := 0
+			c := consumer{
+				fn: func(ctx context.Context, message *sarama.ConsumerMessage) error {
+					attempts++
+					return errors.New("wallet unavailable")
+				},
+				opts: defaultOptions(),
+			}
+			WithDeadLetterProducer(producer)(&c.opts)
+
+			// канал не закрыт: ConsumeClaim должен выйти сам, а не дожидаться следующих сообщений
+			claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+			claim.messages <- &sarama.ConsumerMessage{Topic: "check_balance", Offset: 1}
+			claim.messages <- &sarama.ConsumerMessage{Topic: "check_balance", Offset: 2}
+			session := &fakeSession{ctx: ctx}
+
+			done := make(chan error, 1)
+			go func() { done <- c.ConsumeClaim(session, claim) }()
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("consume claim: %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("consume claim did not stop after cancel")
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if len(session.marked) != 0 {
+				t.Errorf("marked %d messages, want none", len(session.marked))
+			}
+			if len(claim.messages) != 1 {
+				t.Errorf("second message taken after cancel")
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// DeadLetterSuffix - суффикс топика, в который попадают сообщения, так и не обработанные после повторов
+const DeadLetterSuffix = ".dlq"
+
+// заголовки, которые добавляются к сообщению в dlq
+const (
+	HeaderOriginalTopic     = "dlq-original-topic"
+	HeaderOriginalPartition = "dlq-original-partition"
+	HeaderOriginalOffset    = "dlq-original-offset"
+	HeaderError             = "dlq-error"
+	HeaderAttempts          = "dlq-attempts"
+	HeaderFailedAt          = "dlq-failed-at"
+)
+
+// DeadLetterTopic возвращает dlq топик для topic
+func DeadLetterTopic(topic string) string {
+	return topic + DeadLetterSuffix
+}
+
+// SendDeadLetter публикует сообщение в <topic>.dlq с исходными ключом и заголовками
+// и добавляет заголовки с причиной ошибки
+func SendDeadLetter(producer sarama.SyncProducer, message *sarama.ConsumerMessage, cause error, attempts int) error {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+6)
+	for _, h := range message.Headers {
+		if h != nil {
+			headers = append(headers, *h)
+		}
+	}
+
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderOriginalTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalPartition), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(HeaderError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderAttempts), Value: []byte(strconv.Itoa(attempts))},
+		sarama.RecordHeader{Key: []byte(HeaderFailedAt), Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+
+	msg := &sarama.ProducerMessage{
+		Topic:     DeadLetterTopic(message.Topic),
+		Partition: -1,
+		Value:     sarama.ByteEncoder(message.Value),
+		Headers:   headers,
+	}
+	if message.Key != nil {
+		msg.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	_, _, err := producer.SendMessage(msg)
+
+	return err
+}
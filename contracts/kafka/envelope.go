@@ -0,0 +1,129 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"github.com/Iowel/app-saga-service/contracts/logging"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EnvelopeVersion - текущая версия формата конверта
+const EnvelopeVersion = 1
+
+type envelopeKey struct{}
+
+type sagaKey struct{}
+
+type retryKey struct{}
+
+// WithSagaID задает id саги для сообщений, которые отправляются с этим контекстом
+func WithSagaID(ctx context.Context, sagaID int64) context.Context {
+	return context.WithValue(ctx, sagaKey{}, sagaID)
+}
+
+// WithRetry помечает сообщения, которые отправляются с этим контекстом, как повтор команды
+func WithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryKey{}, true)
+}
+
+// InboxStep возвращает ключ обрабатываемого сообщения topic для таблицы обработанных сообщений.
+// Повтор команды получает ключ со своим message_id: участник выполнит его, даже если исходную
+// команду уже обработал, а повторная доставка самого повтора будет пропущена
+func InboxStep(ctx context.Context, topic string) string {
+	if env, ok := EnvelopeFromContext(ctx); ok && env.Retry {
+		return topic + "#retry:" + env.MessageId
+	}
+	return topic
+}
+
+// EnvelopeFromContext возвращает конверт сообщения, которое сейчас обрабатывается
+func EnvelopeFromContext(ctx context.Context) (*events.Envelope, bool) {
+	env, ok := ctx.Value(envelopeKey{}).(*events.Envelope)
+	return env, ok
+}
+
+func contextWithEnvelope(ctx context.Context, env *events.Envelope) context.Context {
+	return context.WithValue(ctx, envelopeKey{}, env)
+}
+
+// Wrap упаковывает payload в конверт. Сообщение, отправленное при обработке другого,
+// наследует его сагу и корреляцию, а причиной становится обрабатываемое сообщение
+func Wrap(ctx context.Context, topic string, payload []byte) ([]byte, error) {
+	env := &events.Envelope{
+		MessageId:  uuid.NewString(),
+		Type:       topic,
+		OccurredAt: timestamppb.Now(),
+		Version:    EnvelopeVersion,
+		Payload:    payload,
+	}
+
+	if cause, ok := EnvelopeFromContext(ctx); ok {
+		env.SagaId = cause.SagaId
+		env.CorrelationId = cause.CorrelationId
+		env.CausationId = cause.MessageId
+	}
+	if sagaID, ok := ctx.Value(sagaKey{}).(int64); ok {
+		env.SagaId = sagaID
+	}
+	// признак повтора не наследуется ответами на повторную команду
+	if retry, ok := ctx.Value(retryKey{}).(bool); ok {
+		env.Retry = retry
+	}
+	// первое сообщение цепочки
+	if env.CorrelationId == "" {
+		env.CorrelationId = env.MessageId
+	}
+	// сохраняем трассировку для сообщений, которые уйдут позже через outbox
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		carrier := propagation.MapCarrier{}
+		otel.GetTextMapPropagator().Inject(ctx, carrier)
+		env.TraceContext = carrier
+	}
+
+	data, err := proto.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal envelope for %s: %w", topic, err)
+	}
+	return data, nil
+}
+
+// Unwrap достает конверт из значения сообщения, false - сообщение старого формата без конверта
+func Unwrap(value []byte) (*events.Envelope, bool) {
+	var env events.Envelope
+	if err := proto.Unmarshal(value, &env); err != nil {
+		return nil, false
+	}
+	// у сообщений без конверта нет поля version, поэтому оно остается нулевым
+	if env.Version == 0 || env.MessageId == "" {
+		return nil, false
+	}
+	return &env, true
+}
+
+// распаковываем сообщение для обработчика: в value кладем payload, конверт - в контекст.
+// Координаты сообщения и его корреляция попадают во все записи лога обработчика
+func unwrapMessage(ctx context.Context, message *sarama.ConsumerMessage) (context.Context, *sarama.ConsumerMessage) {
+	ctx = logging.With(ctx, "topic", message.Topic, "partition", message.Partition, "offset", message.Offset)
+
+	env, ok := Unwrap(message.Value)
+	if !ok {
+		return ctx, message
+	}
+
+	ctx = logging.With(ctx, "message_id", env.MessageId, "correlation_id", env.CorrelationId)
+	if env.SagaId != 0 {
+		ctx = logging.With(ctx, "saga_id", env.SagaId)
+	}
+
+	unwrapped := *message
+	unwrapped.Value = env.Payload
+	return contextWithEnvelope(ctx, env), &unwrapped
+}
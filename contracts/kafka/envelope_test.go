@@ -0,0 +1,107 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// сообщения без конверта от старых producer'ов должны доходить до обработчиков как есть
+func TestUnwrapLegacyMessages(t *testing.T) {
+	order := &events.Order{
+		UserID:     41,
+		OrderID:    57,
+		ProductSKU: 2,
+		Items:      []*events.OrderItem{{Sku: 2, Quantity: 1}},
+		StatusCode: events.OrderStatus_ORDER_STATUS_PAID,
+	}
+
+	legacy := []proto.Message{
+		order,
+		&events.OrderWithProduct{
+			Order:     order,
+			Product:   &events.Product{Sku: 2, Price: 150, Name: "Золото"},
+			Products:  []*events.Product{{Sku: 2, Price: 150}},
+			Available: true,
+			Total:     150,
+		},
+	}
+
+	for _, msg := range legacy {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if env, ok := Unwrap(data); ok {
+			t.Errorf("%T taken for an envelope: %v", msg, env)
+		}
+	}
+}
+
+func TestWrapKeepsCorrelation(t *testing.T) {
+	first, err := Wrap(WithSagaID(context.Background(), 57), "create_order", []byte("order"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cause, ok := Unwrap(first)
+	if !ok {
+		t.Fatal("wrapped message not recognized as envelope")
+	}
+	if cause.SagaId != 57 || cause.CorrelationId != cause.MessageId || cause.Version != EnvelopeVersion {
+		t.Errorf("unexpected first envelope: %v", cause)
+	}
+
+	// ответ на сообщение наследует сагу и корреляцию
+	next, err := Wrap(contextWithEnvelope(context.Background(), cause), "check_product", []byte("check"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, ok := Unwrap(next)
+	if !ok {
+		t.Fatal("wrapped message not recognized as envelope")
+	}
+	if env.SagaId != 57 || env.CorrelationId != cause.CorrelationId || env.CausationId != cause.MessageId {
+		t.Errorf("unexpected next envelope: %v", env)
+	}
+	if env.Type != "check_product" || string(env.Payload) != "check" {
+		t.Errorf("unexpected payload: %v", env)
+	}
+}
+
+// повтор команды проходит мимо отметки об исходной команде, а ответ на него повтором не считается
+func TestRetryInboxStep(t *testing.T) {
+	original, err := Wrap(context.Background(), "check_balance", []byte("order"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retried, err := Wrap(WithRetry(context.Background()), "check_balance", []byte("order"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, _ := Unwrap(original)
+	if step := InboxStep(contextWithEnvelope(context.Background(), env), "check_balance"); step != "check_balance" {
+		t.Errorf("original command step = %s, want check_balance", step)
+	}
+
+	retry, _ := Unwrap(retried)
+	ctx := contextWithEnvelope(context.Background(), retry)
+	if step := InboxStep(ctx, "check_balance"); step != "check_balance#retry:"+retry.MessageId {
+		t.Errorf("retried command step = %s, want key with message id", step)
+	}
+	if step := InboxStep(context.Background(), "check_balance"); step != "check_balance" {
+		t.Errorf("legacy message step = %s, want check_balance", step)
+	}
+
+	reply, err := Wrap(ctx, "balance_checked", []byte("reply"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env, _ := Unwrap(reply); env.Retry {
+		t.Error("reply to a retried command marked as retry")
+	}
+}
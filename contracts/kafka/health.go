@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/IBM/sarama"
+)
+
+var errGroupNotJoined = errors.New("consumer group not joined")
+
+// GroupStatus - состояние подписки consumer'a для проверок готовности.
+// Готов, пока у consumer'a есть сессия в группе
+type GroupStatus struct {
+	mu     sync.Mutex
+	joined bool
+	err    error
+}
+
+func (s *GroupStatus) setJoined(joined bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.joined = joined
+	if joined {
+		s.err = nil
+	}
+}
+
+func (s *GroupStatus) setError(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// Check возвращает ошибку, пока consumer не вошел в группу
+func (s *GroupStatus) Check(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.joined {
+		return nil
+	}
+	if s.err != nil {
+		return s.err
+	}
+	return errGroupNotJoined
+}
+
+// WithStatus задает, куда consumer сообщает о входе в группу и ошибках подписки
+func WithStatus(status *GroupStatus) Option {
+	return func(o *options) {
+		o.status = status
+	}
+}
+
+// BrokerCheck возвращает проверку доступности кластера: запрос метаданных через
+// постоянное подключение, которое пересоздается после ошибки
+func BrokerCheck(brokers []string) func(ctx context.Context) error {
+	var mu sync.Mutex
+	var client sarama.Client
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if client == nil {
+			c, err := sarama.NewClient(brokers, sarama.NewConfig())
+			if err != nil {
+				return err
+			}
+			client = c
+		}
+
+		if err := client.RefreshMetadata(); err != nil {
+			_ = client.Close()
+			client = nil
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	messagesProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_messages_produced_total",
+		Help: "Messages sent to kafka, by topic and result.",
+	}, []string{"topic", "result"})
+
+	messagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_messages_consumed_total",
+		Help: "Messages read from kafka and handled, by topic and result.",
+	}, []string{"topic", "result"})
+
+	handlerErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_handler_errors_total",
+		Help: "Failed handler attempts, including the ones that were retried.",
+	}, []string{"topic"})
+
+	deadLetters = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_dead_letters_total",
+		Help: "Messages moved to the dead-letter topic after all retries.",
+	}, []string{"topic"})
+)
+
+// результат отправки или обработки сообщения для метрик
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
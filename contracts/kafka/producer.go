@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+)
+
+func NewSyncProducer(brokers []string) (sarama.SyncProducer, error) {
+	config := sarama.NewConfig()
+	config.Producer.Partitioner = sarama.NewRandomPartitioner
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer(brokers, config)
+
+	return producer, err
+}
+
+// SendMessage упаковывает message в конверт и отправляет в topic
+func SendMessage(ctx context.Context, producer sarama.SyncProducer, topic string, message []byte) error {
+	value, err := Wrap(ctx, topic, message)
+	if err != nil {
+		return err
+	}
+	return SendRaw(ctx, producer, topic, value)
+}
+
+// SendRaw отправляет уже упакованное сообщение, например из outbox или dlq.
+// Контекст трассировки передается в заголовках сообщения
+func SendRaw(ctx context.Context, producer sarama.SyncProducer, topic string, message []byte) error {
+	msg := &sarama.ProducerMessage{
+		Topic:     topic,
+		Partition: -1,
+		Value:     sarama.ByteEncoder(message),
+	}
+
+	_, span := startProducerSpan(ctx, msg, message)
+	_, _, err := producer.SendMessage(msg)
+	endSpan(span, err)
+	messagesProduced.WithLabelValues(topic, result(err)).Inc()
+
+	return err
+}
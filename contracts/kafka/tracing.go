@@ -0,0 +1,127 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/Iowel/app-saga-service/contracts/kafka"
+
+// заголовки отправляемого сообщения как носитель контекста трассировки
+type producerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+func (c producerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c producerCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c producerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, string(h.Key))
+	}
+	return keys
+}
+
+// заголовки полученного сообщения, только для чтения
+type consumerCarrier []*sarama.RecordHeader
+
+func (c consumerCarrier) Get(key string) string {
+	for _, h := range c {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c consumerCarrier) Set(string, string) {}
+
+func (c consumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for _, h := range c {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
+
+// контекст трассировки, сохраненный в конверте при его создании
+func envelopeTraceContext(ctx context.Context, value []byte) context.Context {
+	env, ok := Unwrap(value)
+	if !ok || len(env.TraceContext) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(env.TraceContext))
+}
+
+// начинаем спан отправки. Сообщения из outbox и dlq отправляются вне исходного запроса,
+// поэтому без активного спана продолжаем трассировку из конверта
+func startProducerSpan(ctx context.Context, msg *sarama.ProducerMessage, value []byte) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = envelopeTraceContext(ctx, value)
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, msg.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "send"),
+			attribute.String("messaging.destination.name", msg.Topic),
+		),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, producerCarrier{msg: msg})
+
+	return ctx, span
+}
+
+// начинаем спан обработки с родителем из заголовков, для сообщений без них - из конверта
+func startConsumerSpan(message *sarama.ConsumerMessage) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), consumerCarrier(message.Headers))
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = envelopeTraceContext(ctx, message.Value)
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, message.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "process"),
+			attribute.String("messaging.destination.name", message.Topic),
+			attribute.String("messaging.destination.partition.id", strconv.Itoa(int(message.Partition))),
+			attribute.Int64("messaging.kafka.offset", message.Offset),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
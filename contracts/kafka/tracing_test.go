@@ -0,0 +1,200 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	return exporter
+}
+
+// отправляем сообщение через mock producer и возвращаем его в виде, в котором его получит consumer
+func sendAndCapture(t *testing.T, send func(producer sarama.SyncProducer) error) *sarama.ConsumerMessage {
+	t.Helper()
+
+	var sent *sarama.ProducerMessage
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		sent = msg
+		return nil
+	})
+
+	if err := send(producer); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := producer.Close(); err != nil {
+		t.Fatalf("close producer: %v", err)
+	}
+
+	value, err := sent.Value.Encode()
+	if err != nil {
+		t.Fatalf("encode value: %v", err)
+	}
+	message := &sarama.ConsumerMessage{Topic: sent.Topic, Value: value}
+	for i := range sent.Headers {
+		message.Headers = append(message.Headers, &sent.Headers[i])
+	}
+	return message
+}
+
+type fakeSession struct {
+	ctx    context.Context
+	marked []*sarama.ConsumerMessage
+}
+
+func (s *fakeSession) Claims() map[string][]int32               { return nil }
+func (s *fakeSession) MemberID() string                         { return "" }
+func (s *fakeSession) GenerationID() int32                      { return 0 }
+func (s *fakeSession) MarkOffset(string, int32, int64, string)  {}
+func (s *fakeSession) Commit()                                  {}
+func (s *fakeSession) ResetOffset(string, int32, int64, string) {}
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.marked = append(s.marked, msg)
+}
+func (s *fakeSession) Context() context.Context { return s.ctx }
+
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// пропускаем сообщение через ConsumeClaim и возвращаем спан, который видел обработчик
+func consume(t *testing.T, message *sarama.ConsumerMessage) trace.SpanContext {
+	t.Helper()
+
+	var handled trace.SpanContext
+	c := consumer{
+		fn: func(ctx context.Context, _ *sarama.ConsumerMessage) error {
+			handled = trace.SpanContextFromContext(ctx)
+			return nil
+		},
+		opts: defaultOptions(),
+	}
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- message
+	close(claim.messages)
+
+	session := &fakeSession{ctx: context.Background()}
+	if err := c.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("consume claim: %v", err)
+	}
+	if len(session.marked) != 1 {
+		t.Fatalf("marked %d messages, want 1", len(session.marked))
+	}
+	return handled
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("span %q not found in %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func TestTraceCrossesKafka(t *testing.T) {
+	exporter := setupTracing(t)
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "CreateOrder")
+	message := sendAndCapture(t, func(producer sarama.SyncProducer) error {
+		return SendMessage(ctx, producer, "create_order", []byte("payload"))
+	})
+	root.End()
+
+	handled := consume(t, message)
+
+	spans := exporter.GetSpans()
+	publish := findSpan(t, spans, "create_order publish")
+	process := findSpan(t, spans, "create_order process")
+
+	if publish.SpanKind != trace.SpanKindProducer || process.SpanKind != trace.SpanKindConsumer {
+		t.Fatalf("unexpected span kinds: publish %v, process %v", publish.SpanKind, process.SpanKind)
+	}
+	if publish.Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("publish span parent = %s, want %s", publish.Parent.SpanID(), root.SpanContext().SpanID())
+	}
+	if process.Parent.SpanID() != publish.SpanContext.SpanID() {
+		t.Fatalf("process span parent = %s, want %s", process.Parent.SpanID(), publish.SpanContext.SpanID())
+	}
+	if process.SpanContext.TraceID() != root.SpanContext().TraceID() {
+		t.Fatalf("process span trace = %s, want %s", process.SpanContext.TraceID(), root.SpanContext().TraceID())
+	}
+	if handled.SpanID() != process.SpanContext.SpanID() {
+		t.Fatalf("handler span = %s, want %s", handled.SpanID(), process.SpanContext.SpanID())
+	}
+}
+
+// сообщение из outbox отправляется без активного спана, трассировка берется из конверта
+func TestOutboxMessageContinuesTrace(t *testing.T) {
+	exporter := setupTracing(t)
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "RequestCancel")
+	value, err := Wrap(ctx, "cancel_request", []byte("payload"))
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	root.End()
+
+	message := sendAndCapture(t, func(producer sarama.SyncProducer) error {
+		return SendRaw(context.Background(), producer, "cancel_request", value)
+	})
+	consume(t, message)
+
+	spans := exporter.GetSpans()
+	publish := findSpan(t, spans, "cancel_request publish")
+	process := findSpan(t, spans, "cancel_request process")
+
+	if publish.Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("publish span parent = %s, want %s", publish.Parent.SpanID(), root.SpanContext().SpanID())
+	}
+	if process.SpanContext.TraceID() != root.SpanContext().TraceID() {
+		t.Fatalf("process span trace = %s, want %s", process.SpanContext.TraceID(), root.SpanContext().TraceID())
+	}
+}
+
+// у сообщений без заголовков и конверта начинается новая трассировка
+func TestLegacyMessageStartsTrace(t *testing.T) {
+	exporter := setupTracing(t)
+
+	handled := consume(t, &sarama.ConsumerMessage{Topic: "create_order", Value: []byte("legacy")})
+
+	process := findSpan(t, exporter.GetSpans(), "create_order process")
+	if process.Parent.IsValid() {
+		t.Fatalf("legacy message span has parent %s", process.Parent.SpanID())
+	}
+	if handled.SpanID() != process.SpanContext.SpanID() {
+		t.Fatalf("handler span = %s, want %s", handled.SpanID(), process.SpanContext.SpanID())
+	}
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fieldsKey struct{}
+
+// Setup делает JSON-логгер с полями из контекста логгером по умолчанию,
+// level - debug, info, warn или error, пустой - info
+func Setup(service string, level string) error {
+	lvl := slog.LevelInfo
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("failed to parse log level %q: %w", level, err)
+		}
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: lvl})
+	slog.SetDefault(slog.New(contextHandler{handler}).With("service", service))
+
+	return nil
+}
+
+// Fatal пишет ошибку и завершает процесс
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// With добавляет поля ко всем записям, которые пишутся с этим контекстом.
+// Поле с тем же ключом заменяет добавленное раньше
+func With(ctx context.Context, args ...any) context.Context {
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(args...)
+
+	parent := fields(ctx)
+	merged := make([]slog.Attr, 0, len(parent)+r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		merged = append(merged, a)
+		return true
+	})
+
+	for i := len(parent) - 1; i >= 0; i-- {
+		if !hasKey(merged, parent[i].Key) {
+			merged = append([]slog.Attr{parent[i]}, merged...)
+		}
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+func fields(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	return attrs
+}
+
+func hasKey(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// добавляет к записи поля из контекста и id трассировки
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(fields(ctx)...)
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+)
+
+func TestContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(contextHandler{slog.NewJSONHandler(&buf, nil)})
+
+	ctx := With(context.Background(), "topic", "create_order", "order_id", int64(1))
+	ctx = With(ctx, "order_id", int64(2), "user_id", int64(7))
+	logger.InfoContext(ctx, "handled")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+
+	want := map[string]any{"topic": "create_order", "order_id": float64(2), "user_id": float64(7)}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if bytes.Count(buf.Bytes(), []byte(`"order_id"`)) != 1 {
+		t.Errorf("order_id logged more than once: %s", buf.String())
+	}
+}
+
+func TestSetupLevel(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	if err := Setup("test", "warn"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if slog.Default().Enabled(context.Background(), slog.LevelInfo) {
+		t.Errorf("info enabled with level warn")
+	}
+	if err := Setup("test", "verbose"); err == nil {
+		t.Errorf("unknown level accepted")
+	}
+}
+
+func TestWithOrder(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(contextHandler{slog.NewJSONHandler(&buf, nil)})
+
+	order := &events.Order{OrderID: 5, UserID: 3, Items: []*events.OrderItem{{Sku: 1}, {Sku: 4}}}
+	logger.InfoContext(WithOrder(context.Background(), order), "handled")
+
+	var entry struct {
+		OrderID int64   `json:"order_id"`
+		UserID  int64   `json:"user_id"`
+		SKU     []int64 `json:"sku"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+	if entry.OrderID != 5 || entry.UserID != 3 || len(entry.SKU) != 2 || entry.SKU[1] != 4 {
+		t.Errorf("unexpected fields: %s", buf.String())
+	}
+}
@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+)
+
+// WithOrder добавляет к записям лога id заказа, пользователя и sku товаров заказа
+func WithOrder(ctx context.Context, order *events.Order) context.Context {
+	if order == nil {
+		return ctx
+	}
+
+	ctx = With(ctx, "order_id", order.OrderID, "user_id", order.UserID)
+
+	// заказ из нескольких позиций логируем списком sku
+	if len(order.Items) > 0 {
+		skus := make([]int64, 0, len(order.Items))
+		for _, item := range order.Items {
+			skus = append(skus, item.GetSku())
+		}
+		return With(ctx, "sku", skus)
+	}
+	if order.ProductSKU != 0 {
+		return With(ctx, "sku", order.ProductSKU)
+	}
+	return ctx
+}
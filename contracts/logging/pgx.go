@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/tracelog"
+)
+
+// QueryTracer пишет запросы репозиториев в лог вместе с полями из контекста:
+// успешные на уровне debug, ошибки - на уровне error. Аргументы запросов не логируются
+func QueryTracer() pgx.QueryTracer {
+	return &tracelog.TraceLog{
+		Logger:   tracelog.LoggerFunc(logQuery),
+		LogLevel: tracelog.LogLevelDebug,
+	}
+}
+
+func logQuery(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+	lvl := slog.LevelDebug
+	switch level {
+	case tracelog.LogLevelError:
+		lvl = slog.LevelError
+	case tracelog.LogLevelWarn:
+		lvl = slog.LevelWarn
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		if key != "args" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, data[key]))
+	}
+
+	slog.LogAttrs(ctx, lvl, "pgx: "+msg, attrs...)
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Addr - адрес, на котором сервисы отдают /metrics
+const Addr = ":2112"
+
+// сколько ждем завершения текущих запросов при остановке
+const shutdownTimeout = 5 * time.Second
+
+// ListenAndServe отдает метрики из prometheus.DefaultRegisterer на addr/metrics
+// вместе со служебными обработчиками сервиса из mux, например проверками здоровья.
+// Сервер останавливается после отмены ctx
+func ListenAndServe(ctx context.Context, addr string, mux *http.ServeMux) error {
+	if mux == nil {
+		mux = http.NewServeMux()
+	}
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-stopped
+	return nil
+}
@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Iowel/app-saga-service/contracts/kafka"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Migration создает таблицу outbox: события, которые нужно опубликовать в kafka вместе с изменениями в базе
+const Migration = `
+	CREATE TABLE IF NOT EXISTS outbox (
+		id BIGSERIAL PRIMARY KEY,
+		topic TEXT NOT NULL,
+		payload BYTEA NOT NULL,
+		created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT NOW(),
+		sent_at TIMESTAMP WITHOUT TIME ZONE
+	);
+
+	CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
+`
+
+type Message struct {
+	ID        int64
+	Topic     string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// Execer - транзакция или пул, в которых сохраняется событие
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Beginner - пул соединений, из которого relay открывает транзакции
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Insert сохраняет событие уже упакованным в конверт, сага и корреляция берутся из ctx.
+// Вызывается в транзакции изменений, с которыми событие должно быть опубликовано
+func Insert(ctx context.Context, q Execer, topic string, payload []byte) error {
+	payload, err := kafka.Wrap(ctx, topic, payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+		INSERT INTO outbox (topic, payload)
+		VALUES ($1, $2)
+	`, topic, payload)
+	if err != nil {
+		return fmt.Errorf("failed insert outbox message: %w", err)
+	}
+	return nil
+}
+
+type Store struct {
+	db Beginner
+}
+
+func NewStore(db Beginner) *Store {
+	return &Store{db: db}
+}
+
+// ProcessPending передает в send неотправленные события в порядке их создания и помечает отправленными.
+// Строки блокируются до конца транзакции, поэтому несколько relay не отправят одно событие дважды.
+// На первой ошибке отправки обработка останавливается, уже отправленные события фиксируются.
+func (s *Store) ProcessPending(ctx context.Context, limit int, send func(msg *Message) error) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT
+			id,
+			topic,
+			payload,
+			created_at
+		FROM
+			outbox
+		WHERE
+			sent_at IS NULL
+		ORDER BY
+			id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query outbox: %w", err)
+	}
+
+	messages, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*Message, error) {
+		var msg Message
+		err := row.Scan(&msg.ID, &msg.Topic, &msg.Payload, &msg.CreatedAt)
+		return &msg, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan outbox: %w", err)
+	}
+
+	sent := 0
+	var sendErr error
+	for _, msg := range messages {
+		if sendErr = send(msg); sendErr != nil {
+			break
+		}
+
+		_, err := tx.Exec(ctx, `UPDATE outbox SET sent_at = NOW() WHERE id = $1`, msg.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark outbox message %d as sent: %w", msg.ID, err)
+		}
+		sent++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	if sendErr != nil {
+		return sent, fmt.Errorf("failed to send outbox message: %w", sendErr)
+	}
+
+	return sent, nil
+}
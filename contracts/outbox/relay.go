@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/Iowel/app-saga-service/contracts/kafka"
+)
+
+// сколько событий отправляем за один проход
+const batchSize = 100
+
+// Relay публикует в kafka события, сохраненные в outbox
+type Relay struct {
+	store    *Store
+	producer sarama.SyncProducer
+	interval time.Duration
+}
+
+func NewRelay(store *Store, producer sarama.SyncProducer, interval time.Duration) *Relay {
+	return &Relay{
+		store:    store,
+		producer: producer,
+		interval: interval,
+	}
+}
+
+// Run отправляет события, пока не будет отменен контекст
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.flush(ctx)
+		}
+	}
+}
+
+// отправляем пачками, пока в outbox есть события
+func (r *Relay) flush(ctx context.Context) {
+	for {
+		sent, err := r.store.ProcessPending(ctx, batchSize, func(msg *Message) error {
+			// в outbox событие уже лежит в конверте
+			return kafka.SendRaw(ctx, r.producer, msg.Topic, msg.Payload)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to relay outbox", "error", err)
+			return
+		}
+		if sent < batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package topics
+
+// команды и ответы саги заказа
+const (
+	// CreateOrder публикует клиентский сервис, сообщение запускает сагу
+	CreateOrder    = "create_order"
+	CheckProduct   = "check_product"
+	ProductChecked = "product_checked"
+	CheckBalance   = "check_balance"
+	BalanceChecked = "balance_checked"
+	// CommitReservation просит склад продать зарезервированный товар,
+	// ответ ReservationCommitted сообщает, удалось ли
+	CommitReservation    = "commit_reservation"
+	ReservationCommitted = "reservation_committed"
+	// CaptureWallet просит кошелек списать холд заказа,
+	// ответ WalletCaptured сообщает, хватило ли денег
+	CaptureWallet  = "capture_wallet"
+	WalletCaptured = "wallet_captured"
+	CommitOrder    = "commit_order"
+	// GetProduct сообщает клиенту о завершенном заказе
+	GetProduct = "get_product"
+)
+
+// отмена и компенсации
+const (
+	// CancelRequest - запрос пользователя на отмену заказа
+	CancelRequest = "cancel_request"
+	// CancelOrder - старый топик отмены, оставлен для совместимости
+	CancelOrder = "cancel_order"
+	// CancelWallet возвращает товар на склад
+	CancelWallet = "cancel_wallet"
+	// RefundWallet снимает холд или возвращает списанные деньги
+	RefundWallet = "refund_wallet"
+)
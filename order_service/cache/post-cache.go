@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"order_service/model"
 )
 
@@ -9,4 +10,5 @@ type IPostCache interface {
 	Get(key string) *model.UserCache
 	GetAll() []*model.UserCache
 	Delete(key string)
+	Ping(ctx context.Context) error
 }
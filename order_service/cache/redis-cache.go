@@ -3,7 +3,8 @@ package cache
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
 	"order_service/model"
 	"time"
 
@@ -42,7 +43,7 @@ func (cache *redisCache) Set(key string, value *model.UserCache) {
 	// парсим полученное сообщение в json
 	json, err := json.Marshal(value)
 	if err != nil {
-		log.Printf("failed to marshal user: %v", err)
+		slog.Error("failed to marshal user", "key", key, "error", err)
 		return
 	}
 
@@ -69,7 +70,7 @@ func (cache *redisCache) Get(key string) *model.UserCache {
 	// распаковываем json сохраненный в кэше редис
 	err = json.Unmarshal([]byte(val), &user)
 	if err != nil {
-		log.Printf("failed to unmarshall user: %v", err)
+		slog.Error("failed to unmarshal user", "key", key, "error", err)
 	}
 
 	return &user
@@ -88,20 +89,20 @@ func (cache *redisCache) GetAll() []*model.UserCache {
 	for {
 		keys, nextPoint, err := client.Scan(ctx, point, "user:*", 100).Result()
 		if err != nil {
-			log.Printf("failed to scan keys from redis: %v", err)
+			slog.Error("failed to scan keys from redis", "error", err)
 			break
 		}
 
 		for _, key := range keys {
 			val, err := client.Get(ctx, key).Result()
 			if err != nil {
-				log.Printf("failed to get value for key %s: %v", key, err)
+				slog.Error("failed to get value from redis", "key", key, "error", err)
 				continue
 			}
 
 			var u model.UserCache
 			if err := json.Unmarshal([]byte(val), &u); err != nil {
-				log.Printf("failed to unmarshal user from key %s: %v", key, err)
+				slog.Error("failed to unmarshal user", "key", key, "error", err)
 				continue
 			}
 
@@ -127,6 +128,17 @@ func (cache *redisCache) Delete(key string) {
 
 	_, err := client.Del(ctx, key).Result()
 	if err != nil {
-		log.Printf("failed to delete key %s: %v", key, err)
+		slog.Error("failed to delete key from redis", "key", key, "error", err)
 	}
 }
+
+// Ping проверяет, что redis отвечает
+func (cache *redisCache) Ping(ctx context.Context) error {
+	client := cache.getClient()
+	defer client.Close()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
+	}
+	return nil
+}
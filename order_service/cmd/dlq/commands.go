@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/Iowel/app-saga-service/contracts/kafka"
+)
+
+// list печатает сообщения dlq топика, подходящие под фильтр
+func list(brokers []string, topic string, f filter) error {
+	count := 0
+	err := readAll(brokers, deadLetterTopic(topic), func(dl *deadLetter) error {
+		if !f.match(dl) {
+			return nil
+		}
+		count++
+
+		fmt.Printf("%d:%d order=%d topic=%s attempts=%s failed_at=%s\n", dl.partition, dl.offset, dl.orderID, dl.originalTopic, dl.attempts, dl.failedAt)
+		fmt.Printf("  error: %s\n", dl.err)
+		if dl.messageID != "" {
+			fmt.Printf("  message_id: %s correlation_id: %s\n", dl.messageID, dl.correlationID)
+		}
+		fmt.Printf("  body:  %s\n", dl.body)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d message(s)\n", count)
+	return nil
+}
+
+// replay отправляет подходящие под фильтр сообщения обратно в исходный топик
+func replay(brokers []string, topic string, f filter) error {
+	producer, err := kafka.NewSyncProducer(brokers)
+	if err != nil {
+		return fmt.Errorf("failed to create producer: %w", err)
+	}
+	defer producer.Close()
+
+	count := 0
+	err = readAll(brokers, deadLetterTopic(topic), func(dl *deadLetter) error {
+		if !f.match(dl) {
+			return nil
+		}
+
+		// сообщение уже в конверте, отправляем как есть, чтобы сохранить его id, корреляцию и трассировку
+		if err := kafka.SendRaw(context.Background(), producer, dl.originalTopic, dl.value); err != nil {
+			return fmt.Errorf("failed to replay %d:%d to %s: %w", dl.partition, dl.offset, dl.originalTopic, err)
+		}
+		count++
+
+		log.Printf("Replayed %d:%d (order %d) to %s", dl.partition, dl.offset, dl.orderID, dl.originalTopic)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d message(s) replayed\n", count)
+	return nil
+}
+
+func deadLetterTopic(topic string) string {
+	if strings.HasSuffix(topic, kafka.DeadLetterSuffix) {
+		return topic
+	}
+	return kafka.DeadLetterTopic(topic)
+}
+
+// readAll читает все партиции топика от начала до текущего конца
+func readAll(brokers []string, topic string, fn func(dl *deadLetter) error) error {
+	client, err := sarama.NewClient(brokers, sarama.NewConfig())
+	if err != nil {
+		return fmt.Errorf("failed to connect to kafka: %w", err)
+	}
+	defer client.Close()
+
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to get partitions of %s: %w", topic, err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	for _, partition := range partitions {
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return fmt.Errorf("failed to get offset of %s/%d: %w", topic, partition, err)
+		}
+		oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return fmt.Errorf("failed to get offset of %s/%d: %w", topic, partition, err)
+		}
+		if oldest >= newest {
+			continue
+		}
+
+		pc, err := consumer.ConsumePartition(topic, partition, oldest)
+		if err != nil {
+			return fmt.Errorf("failed to consume %s/%d: %w", topic, partition, err)
+		}
+
+		for message := range pc.Messages() {
+			if err := fn(parseDeadLetter(message)); err != nil {
+				pc.Close()
+				return err
+			}
+			if message.Offset >= newest-1 {
+				break
+			}
+		}
+		pc.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// dlq - просмотр и повторная отправка сообщений из dead-letter топиков.
+//
+//	dlq list -topic check_balance [-order 57] [-error "no rows"]
+//	dlq replay -topic check_balance -offsets 0:3,0:7
+//	dlq replay -topic check_balance -order 57
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const usage = `usage:
+  dlq list   -topic <topic> [-brokers host:port] [-order id] [-error text]
+  dlq replay -topic <topic> [-brokers host:port] (-offsets p:o,... | -order id | -error text | -all)
+
+topic - исходный топик, суффикс .dlq можно не указывать`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd := os.Args[1]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	brokers := fs.String("brokers", "localhost:9095", "kafka brokers, comma separated")
+	topic := fs.String("topic", "", "original or dlq topic")
+	orderID := fs.Int64("order", 0, "only messages of this order")
+	errText := fs.String("error", "", "only messages whose error contains this text")
+	offsets := fs.String("offsets", "", "replay only these messages, partition:offset comma separated")
+	all := fs.Bool("all", false, "replay every message of the topic")
+	fs.Parse(os.Args[2:])
+
+	if *topic == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	f := filter{orderID: *orderID, errText: *errText}
+	if *offsets != "" {
+		var err error
+		f.positions, err = parsePositions(*offsets)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	brokerList := strings.Split(*brokers, ",")
+
+	switch cmd {
+	case "list":
+		if err := list(brokerList, *topic, f); err != nil {
+			log.Fatal(err)
+		}
+	case "replay":
+		// без фильтра случайно переотправить весь топик нельзя
+		if f.empty() && !*all {
+			log.Fatal("replay needs -offsets, -order, -error or -all")
+		}
+		if err := replay(brokerList, *topic, f); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// parsePositions разбирает список partition:offset
+func parsePositions(value string) (map[position]bool, error) {
+	positions := make(map[position]bool)
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid position %q, want partition:offset", item)
+		}
+
+		partition, err := strconv.ParseInt(parts[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid partition in %q: %w", item, err)
+		}
+		offset, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset in %q: %w", item, err)
+		}
+
+		positions[position{partition: int32(partition), offset: offset}] = true
+	}
+	return positions, nil
+}
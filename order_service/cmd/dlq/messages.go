@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"github.com/Iowel/app-saga-service/contracts/kafka"
+	"github.com/Iowel/app-saga-service/contracts/topics"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// топики, в которых лежит events.Order, в остальных - events.OrderWithProduct
+var orderTopics = map[string]bool{
+	topics.CreateOrder:   true,
+	topics.CheckProduct:  true,
+	topics.CancelRequest: true,
+}
+
+type position struct {
+	partition int32
+	offset    int64
+}
+
+// deadLetter - сообщение из dlq вместе с разобранными заголовками
+type deadLetter struct {
+	position
+	originalTopic string
+	err           string
+	attempts      string
+	failedAt      string
+	messageID     string
+	correlationID string
+	orderID       int64
+	body          string
+	value         []byte
+}
+
+func parseDeadLetter(message *sarama.ConsumerMessage) *deadLetter {
+	dl := &deadLetter{
+		position:      position{partition: message.Partition, offset: message.Offset},
+		originalTopic: strings.TrimSuffix(message.Topic, kafka.DeadLetterSuffix),
+		value:         message.Value,
+	}
+
+	for _, h := range message.Headers {
+		switch string(h.Key) {
+		case kafka.HeaderOriginalTopic:
+			dl.originalTopic = string(h.Value)
+		case kafka.HeaderError:
+			dl.err = string(h.Value)
+		case kafka.HeaderAttempts:
+			dl.attempts = string(h.Value)
+		case kafka.HeaderFailedAt:
+			dl.failedAt = string(h.Value)
+		}
+	}
+
+	// разбираем payload из конверта, у старых сообщений без конверта - само значение
+	payload := message.Value
+	if env, ok := kafka.Unwrap(message.Value); ok {
+		payload = env.Payload
+		dl.messageID = env.MessageId
+		dl.correlationID = env.CorrelationId
+	}
+
+	dl.orderID, dl.body = decode(dl.originalTopic, payload)
+	return dl
+}
+
+// разбираем сообщение по формату исходного топика
+func decode(topic string, value []byte) (int64, string) {
+	var msg proto.Message
+	var orderID func() int64
+
+	if orderTopics[topic] {
+		order := &events.Order{}
+		msg, orderID = order, order.GetOrderID
+	} else {
+		product := &events.OrderWithProduct{}
+		msg, orderID = product, func() int64 { return product.GetOrder().GetOrderID() }
+	}
+
+	if err := proto.Unmarshal(value, msg); err != nil {
+		return 0, fmt.Sprintf("<undecodable %d bytes: %v>", len(value), err)
+	}
+
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		return orderID(), fmt.Sprintf("<failed to format: %v>", err)
+	}
+	return orderID(), string(body)
+}
+
+// filter отбирает сообщения для просмотра и повторной отправки
+type filter struct {
+	orderID   int64
+	errText   string
+	positions map[position]bool
+}
+
+func (f filter) empty() bool {
+	return f.orderID == 0 && f.errText == "" && len(f.positions) == 0
+}
+
+func (f filter) match(dl *deadLetter) bool {
+	if f.orderID != 0 && dl.orderID != f.orderID {
+		return false
+	}
+	if f.errText != "" && !strings.Contains(dl.err, f.errText) {
+		return false
+	}
+	if len(f.positions) > 0 && !f.positions[dl.position] {
+		return false
+	}
+	return true
+}
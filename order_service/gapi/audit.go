@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"order_service/model"
+	"order_service/protos"
+	"order_service/repository"
+	"order_service/saga"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// audited выполняет команду оператора и записывает ее в журнал вместе с состоянием саги до и после.
+// Запись создается до команды: если журнал недоступен, команда не выполняется.
+// Если не удалось записать результат, RPC тоже завершается ошибкой, запись остается без итога
+func (server *Server) audited(ctx context.Context, op string, operation string, req *protos.AdminRequest, fn func(ctx context.Context) ([]string, error)) (*protos.AdminResponse, error) {
+	operator := operatorFromContext(ctx)
+	if operator == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "operator is not authenticated: path; %s", op)
+	}
+
+	entry := &model.AdminAudit{
+		OrderID:   req.OrderId,
+		Operation: operation,
+		Operator:  operator,
+		Comment:   req.Comment,
+	}
+
+	if before, err := server.engine.Saga(ctx, req.OrderId); err == nil {
+		entry.StateBefore = before.State
+	}
+
+	if err := server.auditRepo.Record(ctx, entry); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to audit command, command not executed: path; %s, err: %v", op, err)
+	}
+
+	topics, opErr := fn(ctx)
+	entry.Topics = topics
+	if opErr != nil {
+		entry.Error = opErr.Error()
+	}
+
+	if after, err := server.engine.Saga(ctx, req.OrderId); err == nil {
+		entry.StateAfter = after.State
+	}
+
+	// команда уже выполнена, результат записываем, даже если клиент отключился
+	if err := server.auditRepo.Finish(context.WithoutCancel(ctx), entry); err != nil {
+		slog.ErrorContext(ctx, "failed to audit admin command result", "operation", operation, "order_id", req.OrderId, "audit_id", entry.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "command executed, but its result is not audited, check audit %d: path; %s, err: %v", entry.ID, op, err)
+	}
+
+	if opErr != nil {
+		return nil, toStatus(op, opErr)
+	}
+
+	return &protos.AdminResponse{
+		OrderId: req.OrderId,
+		State:   entry.StateAfter,
+		Topics:  topics,
+	}, nil
+}
+
+// переводим ошибки саги в коды gRPC
+func toStatus(op string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrSagaNotFound):
+		return status.Errorf(codes.NotFound, "saga not found: path; %s, err: %v", op, err)
+	case errors.Is(err, saga.ErrSagaFinished), errors.Is(err, saga.ErrNothingToDo):
+		return status.Errorf(codes.FailedPrecondition, "saga can not be changed: path; %s, err: %v", op, err)
+	case errors.Is(err, saga.ErrStateChanged):
+		return status.Errorf(codes.Aborted, "saga state changed: path; %s, err: %v", op, err)
+	}
+	return status.Errorf(codes.Internal, "failed to execute command: path; %s, err: %v", op, err)
+}
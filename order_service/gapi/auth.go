@@ -0,0 +1,62 @@
+package gapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"order_service/protos"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type operatorKey struct{}
+
+// AuthInterceptor пускает к AdminService только запросы с токеном оператора
+// в метаданных authorization: Bearer <token>. Имя оператора для журнала берется из токена.
+// Остальные сервисы сервера, например health, доступны без токена
+func AuthInterceptor(tokens map[string]string) grpc.UnaryServerInterceptor {
+	prefix := "/" + protos.AdminService_ServiceDesc.ServiceName + "/"
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+
+		operator, ok := authenticate(ctx, tokens)
+		if !ok {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid operator token: path; %s", info.FullMethod)
+		}
+
+		return handler(context.WithValue(ctx, operatorKey{}, operator), req)
+	}
+}
+
+// сравниваем со всеми токенами за одно время, чтобы по задержке нельзя было подобрать токен
+func authenticate(ctx context.Context, tokens map[string]string) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) != 1 {
+		return "", false
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+
+	found := ""
+	for operator, want := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			found = operator
+		}
+	}
+	return found, found != ""
+}
+
+// оператор, которого определил AuthInterceptor
+func operatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
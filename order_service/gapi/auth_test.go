@@ -0,0 +1,54 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	interceptor := AuthInterceptor(map[string]string{"support": "s3cret", "oncall": "t0ken"})
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization []string
+		wantCode      codes.Code
+		wantOperator  string
+	}{
+		{name: "valid token", method: "/protos.AdminService/RetryStep", authorization: []string{"Bearer t0ken"}, wantCode: codes.OK, wantOperator: "oncall"},
+		{name: "no metadata", method: "/protos.AdminService/RetryStep", wantCode: codes.Unauthenticated},
+		{name: "unknown token", method: "/protos.AdminService/MarkResolved", authorization: []string{"Bearer support"}, wantCode: codes.Unauthenticated},
+		{name: "no bearer scheme", method: "/protos.AdminService/ForceCompensate", authorization: []string{"s3cret"}, wantCode: codes.Unauthenticated},
+		{name: "several tokens", method: "/protos.AdminService/RetryStep", authorization: []string{"Bearer s3cret", "Bearer t0ken"}, wantCode: codes.Unauthenticated},
+		{name: "health without token", method: "/grpc.health.v1.Health/Check", wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			for _, value := range tt.authorization {
+				md.Append("authorization", value)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			var operator string
+			handler := func(ctx context.Context, req any) (any, error) {
+				operator = operatorFromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err: %v)", code, tt.wantCode, err)
+			}
+			if operator != tt.wantOperator {
+				t.Fatalf("operator = %q, want %q", operator, tt.wantOperator)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package gapi
+
+import (
+	"context"
+	"order_service/model"
+	"order_service/protos"
+)
+
+func (server *Server) ForceCompensate(ctx context.Context, req *protos.AdminRequest) (*protos.AdminResponse, error) {
+	const op = "gapi.ForceCompensate"
+
+	return server.audited(ctx, op, "force_compensate", req, func(ctx context.Context) ([]string, error) {
+		return server.engine.ForceCompensate(ctx, req.OrderId, model.ReasonCancelledByOperator)
+	})
+}
@@ -0,0 +1,14 @@
+package gapi
+
+import (
+	"context"
+	"order_service/protos"
+)
+
+func (server *Server) MarkResolved(ctx context.Context, req *protos.AdminRequest) (*protos.AdminResponse, error) {
+	const op = "gapi.MarkResolved"
+
+	return server.audited(ctx, op, "mark_resolved", req, func(ctx context.Context) ([]string, error) {
+		return nil, server.engine.MarkResolved(ctx, req.OrderId)
+	})
+}
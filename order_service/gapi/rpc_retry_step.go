@@ -0,0 +1,14 @@
+package gapi
+
+import (
+	"context"
+	"order_service/protos"
+)
+
+func (server *Server) RetryStep(ctx context.Context, req *protos.AdminRequest) (*protos.AdminResponse, error) {
+	const op = "gapi.RetryStep"
+
+	return server.audited(ctx, op, "retry_step", req, func(ctx context.Context) ([]string, error) {
+		return server.engine.Retry(ctx, req.OrderId)
+	})
+}
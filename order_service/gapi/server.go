@@ -0,0 +1,20 @@
+package gapi
+
+import (
+	"order_service/protos"
+	"order_service/repository"
+	"order_service/saga"
+)
+
+type Server struct {
+	protos.UnimplementedAdminServiceServer
+	engine    *saga.Engine
+	auditRepo *repository.AuditRepository
+}
+
+func NewServer(engine *saga.Engine, auditRepo *repository.AuditRepository) (*Server, error) {
+	return &Server{
+		engine:    engine,
+		auditRepo: auditRepo,
+	}, nil
+}
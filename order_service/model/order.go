@@ -0,0 +1,40 @@
+package model
+
+// статусы заказа, в базе хранятся в типе order_status
+const (
+	OrderPending   = "pending"
+	OrderReserved  = "reserved"
+	OrderPaid      = "paid"
+	OrderCompleted = "completed"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+// допустимые переходы статуса заказа
+var orderTransitions = map[string][]string{
+	OrderPending:  {OrderReserved, OrderCancelled},
+	OrderReserved: {OrderPaid, OrderCancelled},
+	// после блокировки денег отмена означает их возврат
+	OrderPaid: {OrderCompleted, OrderRefunded},
+}
+
+// CanTransitionOrder сообщает, можно ли перевести заказ из статуса from в статус to
+func CanTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// коды причин статуса заказа, текст по коду выбирает клиентский сервис на языке пользователя
+const (
+	ReasonCompleted           = "completed"
+	ReasonOutOfStock          = "out_of_stock"
+	ReasonInsufficientFunds   = "insufficient_funds"
+	ReasonCommitFailed        = "commit_failed"
+	ReasonTimeout             = "timeout"
+	ReasonCancelledByUser     = "cancelled_by_user"
+	ReasonCancelledByOperator = "cancelled_by_operator"
+)
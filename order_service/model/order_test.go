@@ -0,0 +1,33 @@
+package model
+
+import "testing"
+
+// проверяем все пары статусов: разрешены только переходы из таблицы, остальные запрещены
+func TestCanTransitionOrder(t *testing.T) {
+	statuses := []string{OrderPending, OrderReserved, OrderPaid, OrderCompleted, OrderCancelled, OrderRefunded}
+
+	allowed := map[[2]string]bool{
+		{OrderPending, OrderReserved}:   true,
+		{OrderPending, OrderCancelled}:  true,
+		{OrderReserved, OrderPaid}:      true,
+		{OrderReserved, OrderCancelled}: true,
+		{OrderPaid, OrderCompleted}:     true,
+		{OrderPaid, OrderRefunded}:      true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]string{from, to}]
+			if got := CanTransitionOrder(from, to); got != want {
+				t.Errorf("CanTransitionOrder(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	// неизвестные и пустые статусы никуда не переходят
+	for _, tt := range [][2]string{{"", OrderPending}, {"unknown", OrderCancelled}, {OrderPending, ""}, {OrderPaid, "unknown"}} {
+		if CanTransitionOrder(tt[0], tt[1]) {
+			t.Errorf("CanTransitionOrder(%q, %q) = true, want false", tt[0], tt[1])
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// общие состояния саги, промежуточные состояния задают шаги ее описания
+const (
+	SagaStarted     = "started"
+	SagaCommitted   = "committed"
+	SagaCancelled   = "cancelled"
+	SagaCompensated = "compensated"
+	// сагу вручную закрыл оператор
+	SagaResolved = "resolved"
+)
+
+// IsTerminal сообщает, что сага в этом состоянии завершена
+func IsTerminal(state string) bool {
+	switch state {
+	case SagaCommitted, SagaCancelled, SagaCompensated, SagaResolved:
+		return true
+	}
+	return false
+}
+
+type SagaInstance struct {
+	OrderID   int64      `json:"order_id"`
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Topic     string     `json:"topic"`
+	Payload   []byte     `json:"payload"`
+	Deadline  *time.Time `json:"deadline"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type SagaStep struct {
+	ID        int64  `json:"id"`
+	OrderID   int64  `json:"order_id"`
+	FromState string `json:"from_state"`
+	ToState   string `json:"to_state"`
+	Topic     string `json:"topic"`
+	StepDetails
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StepDetails - что решила сага на переходе: результат проверки ответа и отправленные компенсации
+type StepDetails struct {
+	Decision      string   `json:"decision"`
+	Compensations []string `json:"compensations"`
+}
+
+// AdminAudit - запись о команде оператора над сагой
+type AdminAudit struct {
+	ID          int64     `json:"id"`
+	OrderID     int64     `json:"order_id"`
+	Operation   string    `json:"operation"`
+	Operator    string    `json:"operator"`
+	Comment     string    `json:"comment"`
+	StateBefore string    `json:"state_before"`
+	StateAfter  string    `json:"state_after"`
+	Topics      []string  `json:"topics"`
+	Error       string    `json:"error"`
+	CreatedAt   time.Time `json:"created_at"`
+}
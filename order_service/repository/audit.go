@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"order_service/model"
+)
+
+// AuditRepository хранит журнал команд операторов над сагами
+type AuditRepository struct {
+	db *Db
+}
+
+func NewAuditRepository(db *Db) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Record записывает команду оператора до ее выполнения
+func (r *AuditRepository) Record(ctx context.Context, entry *model.AdminAudit) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO admin_audit (order_id, operation, operator, comment, state_before)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`,
+		entry.OrderID,
+		entry.Operation,
+		entry.Operator,
+		entry.Comment,
+		entry.StateBefore,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit for order %d: %w", entry.OrderID, err)
+	}
+
+	return nil
+}
+
+// Finish дописывает в запись результат команды
+func (r *AuditRepository) Finish(ctx context.Context, entry *model.AdminAudit) error {
+	topics := entry.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE admin_audit
+		SET state_after = $1, topics = $2, error = $3
+		WHERE id = $4
+	`, entry.StateAfter, topics, entry.Error, entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update audit %d for order %d: %w", entry.ID, entry.OrderID, err)
+	}
+
+	return nil
+}
@@ -3,5 +3,11 @@ package repository
 import "errors"
 
 var (
-	ErrProductNotFound = errors.New("product not found")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrSagaNotFound      = errors.New("saga not found")
+	ErrInvalidTransition = errors.New("invalid saga transition")
+	ErrAlreadyProcessed  = errors.New("message already processed")
+	ErrOrderNotFound     = errors.New("order not found")
+	// ErrInvalidStatusTransition - недопустимый переход статуса заказа
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
 )
@@ -24,21 +24,70 @@ func NewOrderRepository(db *Db) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
+// UpdateStatus переводит заказ в статус status и пишет переход в историю.
+// Повторная установка того же статуса ничего не меняет, недопустимый переход возвращает ErrInvalidStatusTransition.
 func (u *OrderRepository) UpdateStatus(ctx context.Context, orderID int64, status string) error {
-	query := `UPDATE orders SET status = $1 WHERE order_id = $2`
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// блокируем заказ, чтобы параллельные переходы проверялись по очереди
+	var current string
+	err = tx.QueryRow(ctx, `SELECT status FROM orders WHERE order_id = $1 FOR UPDATE`, orderID).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: order %d", ErrOrderNotFound, orderID)
+		}
+		return fmt.Errorf("failed to get status for order %d: %w", orderID, err)
+	}
+
+	if current == status {
+		return nil
+	}
+	if !model.CanTransitionOrder(current, status) {
+		return fmt.Errorf("%w: order %d from %s to %s", ErrInvalidStatusTransition, orderID, current, status)
+	}
 
-	_, err := u.db.Pool.Exec(ctx, query, status, orderID)
+	_, err = tx.Exec(ctx, `UPDATE orders SET status = $1 WHERE order_id = $2`, status, orderID)
 	if err != nil {
 		return fmt.Errorf("failed to update status for order %d: %w", orderID, err)
 	}
 
+	_, err = tx.Exec(ctx, `
+		INSERT INTO order_status_history (order_id, from_status, to_status)
+		VALUES ($1, $2, $3)
+	`, orderID, current, status)
+	if err != nil {
+		return fmt.Errorf("failed to insert status history for order %d: %w", orderID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed commit transaction: %w", err)
+	}
+
 	return nil
 }
 
+// GetStatus возвращает текущий статус заказа
+func (u *OrderRepository) GetStatus(ctx context.Context, orderID int64) (string, error) {
+	var status string
+	err := u.db.Conn(ctx).QueryRow(ctx, `SELECT status FROM orders WHERE order_id = $1`, orderID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%w: order %d", ErrOrderNotFound, orderID)
+		}
+		return "", fmt.Errorf("failed to get status for order %d: %w", orderID, err)
+	}
+	return status, nil
+}
+
+// UpdateReason сохраняет код причины текущего статуса заказа, текст для пользователя по коду подбирает клиентский сервис
 func (u *OrderRepository) UpdateReason(ctx context.Context, orderID int64, reason string) error {
-	query := `UPDATE orders SET reason = $1 WHERE order_id = $2`
+	query := `UPDATE orders SET reason_code = $1 WHERE order_id = $2`
 
-	_, err := u.db.Pool.Exec(ctx, query, reason, orderID)
+	_, err := u.db.Conn(ctx).Exec(ctx, query, reason, orderID)
 	if err != nil {
 		return fmt.Errorf("failed to update reason for order %d: %w", orderID, err)
 	}
@@ -52,24 +101,29 @@ func (u *OrderRepository) UpdateUserStatus(ctx context.Context, status string, u
 		SET status = $1 
 		WHERE user_id = $2
 	`
-	_, err := u.db.Pool.Exec(ctx, query, status, userID)
+	_, err := u.db.Conn(ctx).Exec(ctx, query, status, userID)
 	if err != nil {
 		return fmt.Errorf("failed to update status for order %d: %w", userID, err)
 	}
 	return nil
 }
 
+// RunInTx выполняет fn атомарно, изменения из fn либо применяются все, либо ни одно
+func (u *OrderRepository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return u.db.RunInTx(ctx, fn)
+}
+
 // Update redis cache
 
-func (u *OrderRepository) GetProfileByID(id int) (*model.Profile, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (u *OrderRepository) GetProfileByID(ctx context.Context, id int) (*model.Profile, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	query := `
 	select id, avatar, status, wallet, about from profiles where user_id = $1
 	`
 
-	row := u.db.Pool.QueryRow(ctx, query, id)
+	row := u.db.Conn(ctx).QueryRow(ctx, query, id)
 
 	var profile model.Profile
 
@@ -84,15 +138,15 @@ func (u *OrderRepository) GetProfileByID(id int) (*model.Profile, error) {
 	return &profile, nil
 }
 
-func (u *OrderRepository) GetUserByID(id int) (*model.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (u *OrderRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	query := `
 	select id, email, password, name, avatar, role from users where id = $1
 	`
 
-	row := u.db.Pool.QueryRow(ctx, query, id)
+	row := u.db.Conn(ctx).QueryRow(ctx, query, id)
 
 	var user model.User
 	err := row.Scan(&user.ID, &user.Email, &user.Password, &user.Name, &user.Avatar, &user.Role)
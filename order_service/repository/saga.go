@@ -0,0 +1,435 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order_service/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// срок ожидания шага считаем на стороне базы, последний параметр запроса - таймаут в миллисекундах
+const deadlineExpr = `CASE WHEN $5::BIGINT > 0 THEN NOW() + $5::BIGINT * INTERVAL '1 millisecond' END`
+
+// SagaRepository работает через пул, а не через транзакцию из контекста:
+// состояние саги должно стать видимым до отправки следующей команды, иначе ответ может его не застать.
+// Исключение - Commit, после него команд уже нет
+type SagaRepository struct {
+	db *Db
+}
+
+func NewSagaRepository(db *Db) *SagaRepository {
+	return &SagaRepository{db: db}
+}
+
+// StartSaga создает сагу name для заказа в состоянии started, повторный старт игнорируется.
+// timeout задает срок ожидания ответа на шаге, 0 - без срока.
+func (r *SagaRepository) StartSaga(ctx context.Context, orderID int64, name string, topic string, payload []byte, timeout time.Duration) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO saga_instances (order_id, state, topic, payload, deadline, name)
+		VALUES ($1, $2, $3, $4, `+deadlineExpr+`, $6)
+		ON CONFLICT (order_id) DO NOTHING
+	`, orderID, model.SagaStarted, topic, payload, timeout.Milliseconds(), name)
+	if err != nil {
+		return fmt.Errorf("failed to start saga for order %d: %w", orderID, err)
+	}
+
+	// сага уже была запущена ранее
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	if err := insertStep(ctx, tx, orderID, "", model.SagaStarted, topic, model.StepDetails{}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Transition переводит сагу в новое состояние и записывает шаг в историю вместе с details.
+// Допустимость перехода проверяет canTransition из описания саги.
+// Если сага уже находится в состоянии state, ничего не делает.
+func (r *SagaRepository) Transition(ctx context.Context, orderID int64, state string, topic string, payload []byte, timeout time.Duration, details model.StepDetails, canTransition func(from, to string) bool) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT state FROM saga_instances WHERE order_id = $1 FOR UPDATE`, orderID).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSagaNotFound
+		}
+		return fmt.Errorf("failed to get saga for order %d: %w", orderID, err)
+	}
+
+	if current == state {
+		return nil
+	}
+
+	if !canTransition(current, state) {
+		return fmt.Errorf("%w: order %d from %s to %s", ErrInvalidTransition, orderID, current, state)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE saga_instances
+		SET state = $1, topic = $2, payload = $3, deadline = `+deadlineExpr+`, updated_at = NOW()
+		WHERE order_id = $4
+	`, state, topic, payload, orderID, timeout.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to update saga for order %d: %w", orderID, err)
+	}
+
+	if err := insertStep(ctx, tx, orderID, current, state, topic, details); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Commit блокирует сагу, выполняет complete и переводит сагу в committed в транзакции из контекста,
+// поэтому изменения complete и завершение саги фиксируются вместе. Параллельная отмена или таймаут
+// ждут блокировку и после коммита уже не находят сагу в прежнем состоянии.
+// Возвращает false без вызова complete, если завершать сагу из текущего состояния нельзя.
+// Ошибка complete откатывает изменения и возвращается как есть.
+func (r *SagaRepository) Commit(ctx context.Context, orderID int64, topic string, payload []byte, canTransition func(from, to string) bool, complete func(ctx context.Context) error) (bool, error) {
+	tx, err := r.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT state FROM saga_instances WHERE order_id = $1 FOR UPDATE`, orderID).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrSagaNotFound
+		}
+		return false, fmt.Errorf("failed to get saga for order %d: %w", orderID, err)
+	}
+
+	if !canTransition(current, model.SagaCommitted) {
+		return false, nil
+	}
+
+	if err := complete(WithTx(ctx, tx)); err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE saga_instances
+		SET state = $1, topic = $2, payload = $3, deadline = NULL, updated_at = NOW()
+		WHERE order_id = $4
+	`, model.SagaCommitted, topic, payload, orderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update saga for order %d: %w", orderID, err)
+	}
+
+	if err := insertStep(ctx, tx, orderID, current, model.SagaCommitted, topic, model.StepDetails{}); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *SagaRepository) GetSaga(ctx context.Context, orderID int64) (*model.SagaInstance, error) {
+	row := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			order_id,
+			name,
+			state,
+			topic,
+			payload,
+			deadline,
+			created_at,
+			updated_at
+		FROM
+			saga_instances
+		WHERE
+			order_id = $1
+	`, orderID)
+
+	var saga model.SagaInstance
+	err := row.Scan(&saga.OrderID, &saga.Name, &saga.State, &saga.Topic, &saga.Payload, &saga.Deadline, &saga.CreatedAt, &saga.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSagaNotFound
+		}
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+
+	return &saga, nil
+}
+
+// GetSteps возвращает историю переходов саги в порядке их выполнения
+func (r *SagaRepository) GetSteps(ctx context.Context, orderID int64) ([]*model.SagaStep, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			id,
+			order_id,
+			from_state,
+			to_state,
+			topic,
+			decision,
+			compensations,
+			created_at
+		FROM
+			saga_steps
+		WHERE
+			order_id = $1
+		ORDER BY
+			id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saga steps: %w", err)
+	}
+	defer rows.Close()
+
+	var steps []*model.SagaStep
+	for rows.Next() {
+		var step model.SagaStep
+		if err := rows.Scan(&step.ID, &step.OrderID, &step.FromState, &step.ToState, &step.Topic, &step.Decision, &step.Compensations, &step.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saga step: %w", err)
+		}
+		steps = append(steps, &step)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return steps, nil
+}
+
+// ListUnfinished возвращает саги, которые еще не дошли до конечного состояния
+func (r *SagaRepository) ListUnfinished(ctx context.Context) ([]*model.SagaInstance, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			order_id,
+			name,
+			state,
+			topic,
+			payload,
+			deadline,
+			created_at,
+			updated_at
+		FROM
+			saga_instances
+		WHERE
+			state NOT IN ($1, $2, $3, $4)
+		ORDER BY
+			order_id
+	`, model.SagaCommitted, model.SagaCancelled, model.SagaCompensated, model.SagaResolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unfinished sagas: %w", err)
+	}
+	defer rows.Close()
+
+	var sagas []*model.SagaInstance
+	for rows.Next() {
+		var saga model.SagaInstance
+		if err := rows.Scan(&saga.OrderID, &saga.Name, &saga.State, &saga.Topic, &saga.Payload, &saga.Deadline, &saga.CreatedAt, &saga.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saga: %w", err)
+		}
+		sagas = append(sagas, &saga)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return sagas, nil
+}
+
+// ListOverdue возвращает саги, у которых истек срок ожидания текущего шага
+// или которые отменены по таймауту и еще ждут компенсации
+func (r *SagaRepository) ListOverdue(ctx context.Context) ([]*model.SagaInstance, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			order_id,
+			name,
+			state,
+			topic,
+			payload,
+			deadline,
+			created_at,
+			updated_at
+		FROM
+			saga_instances
+		WHERE
+			deadline < NOW() AND state NOT IN ($1, $2, $3)
+		ORDER BY
+			deadline
+	`, model.SagaCommitted, model.SagaCompensated, model.SagaResolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overdue sagas: %w", err)
+	}
+	defer rows.Close()
+
+	var sagas []*model.SagaInstance
+	for rows.Next() {
+		var saga model.SagaInstance
+		if err := rows.Scan(&saga.OrderID, &saga.Name, &saga.State, &saga.Topic, &saga.Payload, &saga.Deadline, &saga.CreatedAt, &saga.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saga: %w", err)
+		}
+		sagas = append(sagas, &saga)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return sagas, nil
+}
+
+// ResetDeadline заново отсчитывает срок ожидания текущего шага, если сага все еще в состоянии state
+func (r *SagaRepository) ResetDeadline(ctx context.Context, orderID int64, state string, timeout time.Duration) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE saga_instances
+		SET deadline = CASE WHEN $3::BIGINT > 0 THEN NOW() + $3::BIGINT * INTERVAL '1 millisecond' END, updated_at = NOW()
+		WHERE order_id = $1 AND state = $2
+	`, orderID, state, timeout.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to reset deadline for order %d: %w", orderID, err)
+	}
+	return nil
+}
+
+// Expire отменяет сагу по таймауту, если она все еще находится в состоянии state и срок шага истек.
+// Если compensate = true, срок остается выставленным, пока компенсация не будет выполнена.
+// Возвращает false, если сага успела продвинуться дальше.
+func (r *SagaRepository) Expire(ctx context.Context, orderID int64, state string, topic string, compensate bool) (bool, error) {
+	return r.cancel(ctx, orderID, state, topic, compensate, true, model.StepDetails{})
+}
+
+// Cancel отменяет сагу по внешнему запросу или отказу участника, если она все еще находится
+// в состоянии state. Срок выставляется так же, как в Expire, details попадают в историю саги.
+func (r *SagaRepository) Cancel(ctx context.Context, orderID int64, state string, topic string, compensate bool, details model.StepDetails) (bool, error) {
+	return r.cancel(ctx, orderID, state, topic, compensate, false, details)
+}
+
+func (r *SagaRepository) cancel(ctx context.Context, orderID int64, state string, topic string, compensate bool, overdue bool, details model.StepDetails) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE saga_instances
+		SET state = $1, topic = $2, deadline = CASE WHEN $5::BOOLEAN THEN NOW() END, updated_at = NOW()
+		WHERE order_id = $3 AND state = $4 AND (NOT $6::BOOLEAN OR deadline < NOW())
+	`, model.SagaCancelled, topic, orderID, state, compensate, overdue)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel saga for order %d: %w", orderID, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := insertStep(ctx, tx, orderID, state, model.SagaCancelled, topic, details); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// CancelUnstarted создает сагу сразу в состоянии cancelled, чтобы запоздавший запуск ее пропустил.
+// Возвращает false, если сага уже была запущена.
+func (r *SagaRepository) CancelUnstarted(ctx context.Context, orderID int64, name string, topic string, payload []byte) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO saga_instances (order_id, state, topic, payload, name)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_id) DO NOTHING
+	`, orderID, model.SagaCancelled, topic, payload, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel saga for order %d: %w", orderID, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := insertStep(ctx, tx, orderID, "", model.SagaCancelled, topic, model.StepDetails{}); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// CancelOrigin возвращает состояние, из которого сага была переведена в cancelled
+func (r *SagaRepository) CancelOrigin(ctx context.Context, orderID int64) (string, error) {
+	var from string
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			from_state
+		FROM
+			saga_steps
+		WHERE
+			order_id = $1 AND to_state = $2
+		ORDER BY
+			id DESC
+		LIMIT 1
+	`, orderID, model.SagaCancelled).Scan(&from)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrSagaNotFound
+		}
+		return "", fmt.Errorf("failed to get cancel origin for order %d: %w", orderID, err)
+	}
+
+	return from, nil
+}
+
+func insertStep(ctx context.Context, tx pgx.Tx, orderID int64, from, to, topic string, details model.StepDetails) error {
+	compensations := details.Compensations
+	if compensations == nil {
+		compensations = []string{}
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO saga_steps (order_id, from_state, to_state, topic, decision, compensations)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, orderID, from, to, topic, details.Decision, compensations)
+	if err != nil {
+		return fmt.Errorf("failed to insert saga step for order %d: %w", orderID, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX общий интерфейс пула соединений и транзакции
+type DBTX interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// WithTx кладет транзакцию в контекст, чтобы методы репозиториев выполнялись внутри нее
+func WithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// Conn возвращает транзакцию из контекста, а если ее нет - пул соединений
+func (d *Db) Conn(ctx context.Context) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return d.Pool
+}
+
+// RunInTx выполняет fn в транзакции. Если транзакция уже есть в контексте, fn выполняется
+// в точке сохранения, и ее ошибка не ломает внешнюю транзакцию.
+func (d *Db) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := d.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(WithTx(ctx, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,141 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"order_service/model"
+	"order_service/repository"
+
+	"github.com/Iowel/app-saga-service/contracts/kafka"
+)
+
+// AdminTopic - топик, которым помечаются переходы саги по командам оператора
+const AdminTopic = "saga_admin"
+
+var (
+	ErrSagaFinished = errors.New("saga already finished")
+	ErrNothingToDo  = errors.New("saga has nothing to retry")
+	ErrStateChanged = errors.New("saga state changed concurrently, try again")
+	ErrUnknownSaga  = errors.New("unknown saga definition")
+)
+
+// Saga возвращает текущее состояние саги
+func (e *Engine) Saga(ctx context.Context, key int64) (*model.SagaInstance, error) {
+	return e.store.GetSaga(ctx, key)
+}
+
+// Retry повторно отправляет команду, ответа на которую ждет сага, и заново отсчитывает срок шага.
+// Для саги, отмененной с неотправленными компенсациями, повторяет компенсации.
+// Команды помечаются как повтор: участник выполняет их заново и отвечает еще раз,
+// даже если уже обработал, потому что потерян мог быть ответ.
+func (e *Engine) Retry(ctx context.Context, key int64) ([]string, error) {
+	ctx = kafka.WithRetry(ctx)
+
+	saga, def, err := e.load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if saga.State == model.SagaCancelled {
+		// срок у отмененной саги остается, только пока компенсации не отправлены
+		if saga.Deadline == nil {
+			return nil, ErrNothingToDo
+		}
+		from, err := e.store.CancelOrigin(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return e.compensateCancelled(ctx, def, key, from, AdminTopic, saga.Payload)
+	}
+
+	if model.IsTerminal(saga.State) {
+		return nil, ErrSagaFinished
+	}
+
+	topic := def.pendingCommand(saga.State)
+	if topic == "" {
+		return nil, fmt.Errorf("%w: unknown state %s", ErrNothingToDo, saga.State)
+	}
+
+	slog.InfoContext(ctx, "retrying saga command", "order_id", key, "command", topic, "state", saga.State)
+	if err := e.send(ctx, key, topic, saga.Payload); err != nil {
+		return nil, err
+	}
+
+	if err := e.store.ResetDeadline(ctx, key, saga.State, def.waitTimeout(saga.State)); err != nil {
+		return nil, err
+	}
+
+	return []string{topic}, nil
+}
+
+// ForceCompensate отменяет незавершенную сагу и отправляет компенсации выполненных шагов,
+// для уже отмененной саги повторяет компенсации
+func (e *Engine) ForceCompensate(ctx context.Context, key int64, reason string) ([]string, error) {
+	saga, def, err := e.load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if saga.State == model.SagaCancelled {
+		from, err := e.store.CancelOrigin(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return e.compensateCancelled(ctx, def, key, from, AdminTopic, saga.Payload)
+	}
+
+	if model.IsTerminal(saga.State) {
+		return nil, ErrSagaFinished
+	}
+
+	cancelled, compensations, err := e.cancel(ctx, def, saga, AdminTopic, reason, false)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrStateChanged
+	}
+
+	return compensations, nil
+}
+
+// MarkResolved закрывает сагу, которую оператор исправил вручную, ничего не отправляя участникам
+func (e *Engine) MarkResolved(ctx context.Context, key int64) error {
+	saga, def, err := e.load(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	// закрыть можно любую сагу, кроме успешно завершенной
+	canResolve := func(from, to string) bool {
+		return from != model.SagaCommitted && from != model.SagaResolved
+	}
+
+	err = e.store.Transition(ctx, key, model.SagaResolved, AdminTopic, saga.Payload, 0, model.StepDetails{}, canResolve)
+	if errors.Is(err, repository.ErrInvalidTransition) {
+		return fmt.Errorf("%w: %v", ErrSagaFinished, err)
+	}
+	// отмененная сага уже учтена при отмене
+	if err == nil && !model.IsTerminal(saga.State) {
+		e.finished(ctx, def, key, outcomeResolved, "")
+	}
+	return err
+}
+
+// загружаем сагу вместе с ее описанием
+func (e *Engine) load(ctx context.Context, key int64) (*model.SagaInstance, *Definition, error) {
+	saga, err := e.store.GetSaga(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	def, ok := e.defs[saga.Name]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownSaga, saga.Name)
+	}
+
+	return saga, def, nil
+}
@@ -0,0 +1,121 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"order_service/model"
+	"order_service/repository"
+
+	"github.com/IBM/sarama"
+	"github.com/Iowel/app-saga-service/contracts/logging"
+)
+
+// сколько раз пробуем отменить сагу, которая продвигается параллельно с отменой
+const cancelAttempts = 3
+
+// Cancel отменяет сагу name по внешнему запросу и откатывает то, что успели сделать участники.
+// Если сага еще не запущена, запоминает отмену, и запуск ее пропустит.
+// Возвращает false, если сага уже завершена.
+func (e *Engine) Cancel(ctx context.Context, name string, key int64, reason string, message *sarama.ConsumerMessage) (bool, error) {
+	def, ok := e.defs[name]
+	if !ok {
+		return false, fmt.Errorf("unknown saga %s", name)
+	}
+	ctx = logging.With(ctx, "order_id", key, "saga", def.Name)
+
+	for i := 0; i < cancelAttempts; i++ {
+		saga, err := e.store.GetSaga(ctx, key)
+		if errors.Is(err, repository.ErrSagaNotFound) {
+			cancelled, err := e.store.CancelUnstarted(ctx, key, def.Name, message.Topic, message.Value)
+			if err != nil {
+				return false, err
+			}
+			if !cancelled {
+				// сагу успели запустить, отменяем ее как обычно
+				continue
+			}
+
+			slog.InfoContext(ctx, "saga cancelled before start", "reason", reason)
+			// сага не запускалась, поэтому считаем только причину
+			sagaCancellations.WithLabelValues(def.Name, reason).Inc()
+			if err := def.Cancel(ctx, key, reason); err != nil {
+				slog.ErrorContext(ctx, "failed to cancel order", "reason", reason, "error", err)
+			}
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if model.IsTerminal(saga.State) {
+			return false, nil
+		}
+
+		cancelled, _, err := e.cancel(ctx, def, saga, message.Topic, reason, false)
+		if err != nil || cancelled {
+			return cancelled, err
+		}
+	}
+
+	return false, fmt.Errorf("failed to cancel saga for order %d: state keeps changing", key)
+}
+
+// переводим сагу в cancelled из текущего состояния и запускаем компенсации.
+// overdue = true отменяет сагу, только если истек срок шага.
+// Возвращает false, если сага успела продвинуться дальше, и отправленные компенсации.
+func (e *Engine) cancel(ctx context.Context, def *Definition, saga *model.SagaInstance, topic string, reason string, overdue bool) (bool, []string, error) {
+	// если ответа на текущий шаг не было и его компенсацию нельзя слать вслепую,
+	// откат делаем только при получении запоздалого ответа
+	compensate := len(def.cancelCompensations(saga.State)) > 0
+	ctx = logging.With(ctx, "order_id", saga.OrderID, "saga", def.Name)
+
+	var cancelled bool
+	var err error
+	if overdue {
+		cancelled, err = e.store.Expire(ctx, saga.OrderID, saga.State, topic, compensate)
+	} else {
+		cancelled, err = e.store.Cancel(ctx, saga.OrderID, saga.State, topic, compensate, model.StepDetails{})
+	}
+	if err != nil || !cancelled {
+		return false, nil, err
+	}
+
+	slog.InfoContext(ctx, "saga cancelled", "state", saga.State, "cancel_topic", topic, "reason", reason)
+	e.finished(ctx, def, saga.OrderID, outcomeCancelled, reason)
+
+	if err := def.Cancel(ctx, saga.OrderID, reason); err != nil {
+		slog.ErrorContext(ctx, "failed to cancel order", "reason", reason, "error", err)
+	}
+
+	if !compensate {
+		return true, nil, nil
+	}
+
+	compensations, err := e.compensateCancelled(ctx, def, saga.OrderID, saga.State, topic, saga.Payload)
+	return true, compensations, err
+}
+
+// откатываем шаги саги, отмененной в состоянии state. Пока компенсации не отправлены,
+// у саги остается срок, и планировщик таймаутов повторит их
+func (e *Engine) compensateCancelled(ctx context.Context, def *Definition, key int64, state string, topic string, payload []byte) ([]string, error) {
+	compensations := def.cancelCompensations(state)
+	return compensations, e.compensate(ctx, def, key, compensations, topic, payload)
+}
+
+// отправляем компенсации и завершаем отмененную сагу
+func (e *Engine) compensate(ctx context.Context, def *Definition, key int64, compensations []string, topic string, payload []byte) error {
+	for _, compensation := range compensations {
+		if err := e.send(ctx, key, compensation, payload); err != nil {
+			return err
+		}
+	}
+
+	message := &sarama.ConsumerMessage{
+		Topic: topic,
+		Value: payload,
+	}
+	_, err := e.transition(ctx, def, key, model.SagaCompensated, message, model.StepDetails{Compensations: compensations})
+	return err
+}
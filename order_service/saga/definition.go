@@ -0,0 +1,168 @@
+package saga
+
+import (
+	"context"
+	"order_service/model"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Step описывает шаг саги: команду участнику и его ответ
+type Step struct {
+	// Name - состояние саги после получения ответа на шаг
+	Name string
+	// Command - топик, в который отправляется команда участнику
+	Command string
+	// Reply - топик, из которого приходит ответ участника
+	Reply string
+	// Success решает по ответу, можно ли переходить к следующему шагу
+	Success func(reply []byte) (bool, error)
+	// Decision - название проверки Success для истории саги, например available
+	Decision string
+	// Reason - код причины отмены, если Success вернул false
+	Reason string
+	// Done выполняет локальные изменения после успешного ответа, до отправки следующей команды
+	Done func(ctx context.Context, key int64) error
+	// Compensation - топики, которые откатывают шаг, если сага отменяется после его выполнения
+	Compensation []string
+	// CompensateOnTimeout - компенсацию можно отправить, даже если ответа на шаг не было
+	// (по таймауту или при отмене по запросу), участник сам проверит, выполнял ли он шаг
+	CompensateOnTimeout bool
+	// Timeout - сколько ждем ответа участника, 0 - без срока
+	Timeout time.Duration
+}
+
+// решение по ответу на шаг в виде, в котором оно попадает в историю саги
+func (s Step) decision(success bool) string {
+	if s.Decision == "" {
+		return strconv.FormatBool(success)
+	}
+	return s.Decision + "=" + strconv.FormatBool(success)
+}
+
+// Definition описывает сагу целиком, выполняет ее Engine
+type Definition struct {
+	Name string
+	// Trigger - топик, сообщение из которого запускает сагу
+	Trigger string
+	Steps   []Step
+	// Commit - топик, через который сага завершается после успешного последнего шага
+	Commit string
+	// CommitTimeout - сколько ждем завершения после последнего шага
+	CommitTimeout time.Duration
+	// Key извлекает ключ саги из сообщения любого ее топика
+	Key func(message *sarama.ConsumerMessage) (int64, error)
+	// Complete выполняет локальные изменения при успешном завершении, ошибка запускает компенсации
+	Complete func(ctx context.Context, key int64, payload []byte) error
+	// Cancel помечает сагу отмененной с указанным кодом причины
+	Cancel func(ctx context.Context, key int64, reason string) error
+	// CommitFailedReason - код причины отмены, если Complete вернул ошибку
+	CommitFailedReason string
+	// TimeoutReason - код причины отмены по таймауту
+	TimeoutReason string
+}
+
+// CanTransition проверяет, можно ли перевести сагу из состояния from в состояние to
+func (d *Definition) CanTransition(from, to string) bool {
+	if from == model.SagaCancelled {
+		return to == model.SagaCompensated
+	}
+	if model.IsTerminal(from) {
+		return false
+	}
+	if to == model.SagaCancelled || to == model.SagaCompensated {
+		return true
+	}
+	return d.next(from) == to
+}
+
+// состояние саги после from при успешном ходе
+func (d *Definition) next(from string) string {
+	if from == model.SagaStarted {
+		return d.Steps[0].Name
+	}
+
+	i := d.stepIndex(from)
+	switch {
+	case i < 0:
+		return ""
+	case i+1 < len(d.Steps):
+		return d.Steps[i+1].Name
+	}
+	return model.SagaCommitted
+}
+
+// номер шага, после ответа на который сага находится в состоянии state, -1 для started
+func (d *Definition) stepIndex(state string) int {
+	for i, step := range d.Steps {
+		if step.Name == state {
+			return i
+		}
+	}
+	return -1
+}
+
+// состояние саги, в котором она ждет ответа на шаг index
+func (d *Definition) stateBefore(index int) string {
+	if index == 0 {
+		return model.SagaStarted
+	}
+	return d.Steps[index-1].Name
+}
+
+// сколько сага может находиться в состоянии state в ожидании следующего ответа
+func (d *Definition) waitTimeout(state string) time.Duration {
+	if model.IsTerminal(state) {
+		return 0
+	}
+
+	i := d.stepIndex(state)
+	if state != model.SagaStarted && i < 0 {
+		return 0
+	}
+	if i+1 < len(d.Steps) {
+		return d.Steps[i+1].Timeout
+	}
+	return d.CommitTimeout
+}
+
+// команда, ответа на которую ждет сага в состоянии state
+func (d *Definition) pendingCommand(state string) string {
+	i := d.stepIndex(state)
+	if state != model.SagaStarted && i < 0 {
+		return ""
+	}
+	if i+1 < len(d.Steps) {
+		return d.Steps[i+1].Command
+	}
+	return d.Commit
+}
+
+// компенсации выполненных шагов с номерами меньше done, в обратном порядке
+func (d *Definition) compensations(done int) []string {
+	var topics []string
+	for i := done - 1; i >= 0; i-- {
+		topics = append(topics, d.Steps[i].Compensation...)
+	}
+	return topics
+}
+
+// отказ на шаге index, index = len(Steps) - отказ коммита: сага отменяется из состояния,
+// в котором ждала ответа, и откатываются только шаги до index, сам шаг выполнен не был
+func (d *Definition) failure(index int) (state string, compensations []string) {
+	return d.stateBefore(index), d.compensations(index)
+}
+
+// компенсации для саги, отмененной по таймауту или по запросу в состоянии state:
+// выполненные шаги и шаг без ответа, если его компенсацию можно отправлять вслепую
+func (d *Definition) cancelCompensations(state string) []string {
+	done := d.stepIndex(state) + 1
+
+	var topics []string
+	if done < len(d.Steps) && d.Steps[done].CompensateOnTimeout {
+		topics = append(topics, d.Steps[done].Compensation...)
+	}
+	return append(topics, d.compensations(done)...)
+}
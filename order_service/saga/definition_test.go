@@ -0,0 +1,193 @@
+package saga
+
+import (
+	"order_service/model"
+	"slices"
+	"testing"
+	"time"
+)
+
+// сага из трех шагов: у первого компенсация только после ответа, у второго - и вслепую,
+// у третьего компенсации нет
+func testDefinition() *Definition {
+	return &Definition{
+		Name:    "order",
+		Trigger: "create_order",
+		Steps: []Step{
+			{
+				Name:         "product_checked",
+				Command:      "check_product",
+				Reply:        "product_checked",
+				Compensation: []string{"cancel_wallet"},
+				Timeout:      10 * time.Second,
+			},
+			{
+				Name:                "balance_checked",
+				Command:             "check_balance",
+				Reply:               "balance_checked",
+				Compensation:        []string{"refund_wallet"},
+				CompensateOnTimeout: true,
+				Timeout:             20 * time.Second,
+			},
+			{
+				Name:    "reservation_committed",
+				Command: "commit_reservation",
+				Reply:   "reservation_committed",
+				Timeout: 30 * time.Second,
+			},
+		},
+		Commit:        "commit_order",
+		CommitTimeout: 40 * time.Second,
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	def := testDefinition()
+
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{model.SagaStarted, "product_checked", true},
+		{"product_checked", "balance_checked", true},
+		{"balance_checked", "reservation_committed", true},
+		{"reservation_committed", model.SagaCommitted, true},
+		{model.SagaStarted, model.SagaCancelled, true},
+		{"balance_checked", model.SagaCompensated, true},
+		{model.SagaCancelled, model.SagaCompensated, true},
+
+		// шаги нельзя пропускать, повторять и проходить назад
+		{model.SagaStarted, "balance_checked", false},
+		{model.SagaStarted, model.SagaCommitted, false},
+		{"product_checked", "product_checked", false},
+		{"balance_checked", "product_checked", false},
+		{"product_checked", model.SagaCommitted, false},
+		{"unknown", "product_checked", false},
+
+		// из завершенной саги переходов нет, кроме компенсации отмененной
+		{model.SagaCommitted, model.SagaCancelled, false},
+		{model.SagaCompensated, model.SagaCancelled, false},
+		{model.SagaResolved, model.SagaCompensated, false},
+		{model.SagaCancelled, model.SagaCommitted, false},
+		{model.SagaCancelled, model.SagaCancelled, false},
+	}
+
+	for _, tt := range tests {
+		if got := def.CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPendingCommandAndTimeout(t *testing.T) {
+	def := testDefinition()
+
+	tests := []struct {
+		state       string
+		wantCommand string
+		wantTimeout time.Duration
+	}{
+		{model.SagaStarted, "check_product", 10 * time.Second},
+		{"product_checked", "check_balance", 20 * time.Second},
+		{"balance_checked", "commit_reservation", 30 * time.Second},
+		{"reservation_committed", "commit_order", 40 * time.Second},
+		{"unknown", "", 0},
+		{model.SagaCommitted, "", 0},
+		{model.SagaCancelled, "", 0},
+	}
+
+	for _, tt := range tests {
+		if got := def.pendingCommand(tt.state); got != tt.wantCommand {
+			t.Errorf("pendingCommand(%s) = %q, want %q", tt.state, got, tt.wantCommand)
+		}
+		if got := def.waitTimeout(tt.state); got != tt.wantTimeout {
+			t.Errorf("waitTimeout(%s) = %v, want %v", tt.state, got, tt.wantTimeout)
+		}
+	}
+}
+
+// отказ участника на шаге done: откатываются только шаги до него, последний - первым
+func TestCompensations(t *testing.T) {
+	def := testDefinition()
+
+	tests := []struct {
+		done int
+		want []string
+	}{
+		{0, nil},
+		{1, []string{"cancel_wallet"}},
+		{2, []string{"refund_wallet", "cancel_wallet"}},
+		{3, []string{"refund_wallet", "cancel_wallet"}},
+	}
+
+	for _, tt := range tests {
+		if got := def.compensations(tt.done); !slices.Equal(got, tt.want) {
+			t.Errorf("compensations(%d) = %v, want %v", tt.done, got, tt.want)
+		}
+	}
+}
+
+// отказ участника: сага отменяется из состояния перед шагом, шаг не считается пройденным,
+// и его компенсация не отправляется, даже если ее можно слать вслепую
+func TestFailure(t *testing.T) {
+	def := testDefinition()
+
+	tests := []struct {
+		name              string
+		index             int
+		wantState         string
+		wantCompensations []string
+	}{
+		{"product out of stock", 0, model.SagaStarted, nil},
+		{"insufficient funds", 1, "product_checked", []string{"cancel_wallet"}},
+		{"reservation not committed", 2, "balance_checked", []string{"refund_wallet", "cancel_wallet"}},
+		{"commit failed", 3, "reservation_committed", []string{"refund_wallet", "cancel_wallet"}},
+	}
+
+	for _, tt := range tests {
+		state, compensations := def.failure(tt.index)
+		if state != tt.wantState {
+			t.Errorf("%s: state = %s, want %s", tt.name, state, tt.wantState)
+		}
+		if !slices.Equal(compensations, tt.wantCompensations) {
+			t.Errorf("%s: compensations = %v, want %v", tt.name, compensations, tt.wantCompensations)
+		}
+		if !def.CanTransition(state, model.SagaCancelled) {
+			t.Errorf("%s: saga can't be cancelled from %s", tt.name, state)
+		}
+	}
+}
+
+// таймаут или отмена по запросу: к выполненным шагам добавляется компенсация шага без ответа,
+// если ее можно отправить вслепую
+func TestCancelCompensations(t *testing.T) {
+	def := testDefinition()
+
+	tests := []struct {
+		state string
+		want  []string
+	}{
+		// ответа на check_product не было, cancel_wallet вслепую не отправляется
+		{model.SagaStarted, nil},
+		// ответа на check_balance не было, но холд мог быть создан
+		{"product_checked", []string{"refund_wallet", "cancel_wallet"}},
+		{"balance_checked", []string{"refund_wallet", "cancel_wallet"}},
+		{"reservation_committed", []string{"refund_wallet", "cancel_wallet"}},
+	}
+
+	for _, tt := range tests {
+		if got := def.cancelCompensations(tt.state); !slices.Equal(got, tt.want) {
+			t.Errorf("cancelCompensations(%s) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestStepDecision(t *testing.T) {
+	step := Step{Decision: "available"}
+	if got := step.decision(false); got != "available=false" {
+		t.Errorf("decision = %q, want available=false", got)
+	}
+	if got := (Step{}).decision(true); got != "true" {
+		t.Errorf("decision without name = %q, want true", got)
+	}
+}
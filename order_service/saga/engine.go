@@ -0,0 +1,249 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"order_service/model"
+	"order_service/repository"
+
+	"github.com/IBM/sarama"
+	"github.com/Iowel/app-saga-service/contracts/kafka"
+	"github.com/Iowel/app-saga-service/contracts/logging"
+)
+
+// HandlerFunc обрабатывает сообщение одного из топиков саги
+type HandlerFunc func(ctx context.Context, message *sarama.ConsumerMessage) error
+
+// Engine выполняет зарегистрированные саги: рассылает команды шагов,
+// разбирает ответы участников и запускает компенсации
+type Engine struct {
+	producer sarama.SyncProducer
+	store    *repository.SagaRepository
+	defs     map[string]*Definition
+	handlers map[string]HandlerFunc
+}
+
+func NewEngine(producer sarama.SyncProducer, store *repository.SagaRepository) *Engine {
+	return &Engine{
+		producer: producer,
+		store:    store,
+		defs:     make(map[string]*Definition),
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// Register добавляет описание саги и обработчики всех ее топиков
+func (e *Engine) Register(def *Definition) {
+	e.defs[def.Name] = def
+
+	e.handlers[def.Trigger] = func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		return e.start(ctx, def, message)
+	}
+
+	for i := range def.Steps {
+		index := i
+		e.handlers[def.Steps[i].Reply] = func(ctx context.Context, message *sarama.ConsumerMessage) error {
+			return e.reply(ctx, def, index, message)
+		}
+	}
+
+	e.handlers[def.Commit] = func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		return e.commit(ctx, def, message)
+	}
+}
+
+// Handlers возвращает обработчики по топикам, на которые нужно подписаться
+func (e *Engine) Handlers() map[string]HandlerFunc {
+	return e.handlers
+}
+
+// Resume повторно обрабатывает последнее сообщение незавершенных саг, чтобы продолжить их после рестарта
+func (e *Engine) Resume(ctx context.Context) error {
+	sagas, err := e.store.ListUnfinished(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, saga := range sagas {
+		ctx := logging.With(ctx, "order_id", saga.OrderID, "saga", saga.Name, "state", saga.State, "topic", saga.Topic)
+
+		handler, ok := e.handlers[saga.Topic]
+		if !ok {
+			slog.WarnContext(ctx, "no handler to resume saga")
+			continue
+		}
+
+		slog.InfoContext(ctx, "resuming saga")
+		message := &sarama.ConsumerMessage{
+			Topic: saga.Topic,
+			Value: saga.Payload,
+		}
+		if err := handler(ctx, message); err != nil {
+			slog.ErrorContext(ctx, "failed to resume saga", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// запускаем сагу и отправляем команду первого шага
+func (e *Engine) start(ctx context.Context, def *Definition, message *sarama.ConsumerMessage) error {
+	key, err := def.Key(message)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, "order_id", key, "saga", def.Name)
+
+	// сагу отменили раньше, чем пришел запуск
+	saga, err := e.store.GetSaga(ctx, key)
+	if err == nil && model.IsTerminal(saga.State) {
+		slog.InfoContext(ctx, "saga already finished, skip start", "state", saga.State)
+		return nil
+	}
+
+	slog.InfoContext(ctx, "starting saga")
+
+	if err := e.store.StartSaga(ctx, key, def.Name, message.Topic, message.Value, def.waitTimeout(model.SagaStarted)); err != nil {
+		return err
+	}
+
+	return e.send(ctx, key, def.Steps[0].Command, message.Value)
+}
+
+// разбираем ответ участника на шаг index: идем дальше или отменяем сагу
+func (e *Engine) reply(ctx context.Context, def *Definition, index int, message *sarama.ConsumerMessage) error {
+	step := def.Steps[index]
+
+	key, err := def.Key(message)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, "order_id", key, "saga", def.Name, "step", step.Name)
+
+	success, err := step.Success(message.Value)
+	if err != nil {
+		return err
+	}
+
+	details := model.StepDetails{Decision: step.decision(success)}
+
+	// отказ участника: шаг не выполнен, поэтому сага отменяется из состояния перед ним
+	// и откатываются только предыдущие шаги
+	if !success {
+		return e.fail(ctx, def, key, index, step.Reason, message, details)
+	}
+
+	ok, err := e.transition(ctx, def, key, step.Name, message, details)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return e.compensateLateReply(ctx, def, index, key, message)
+	}
+
+	if step.Done != nil {
+		if err := step.Done(ctx, key); err != nil {
+			return fmt.Errorf("failed to complete step %s for OrderID %d: %w", step.Name, key, err)
+		}
+	}
+
+	if index+1 < len(def.Steps) {
+		slog.InfoContext(ctx, "step done, sending next command", "command", def.Steps[index+1].Command)
+		return e.send(ctx, key, def.Steps[index+1].Command, message.Value)
+	}
+
+	slog.InfoContext(ctx, "step done, committing saga")
+	return e.send(ctx, key, def.Commit, message.Value)
+}
+
+// завершаем сагу после успешного последнего шага
+func (e *Engine) commit(ctx context.Context, def *Definition, message *sarama.ConsumerMessage) error {
+	key, err := def.Key(message)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, "order_id", key, "saga", def.Name)
+
+	// заказ выдаем под блокировкой саги: отмена или таймаут, пришедшие параллельно,
+	// дождутся коммита, а сагу, которую уже отменили, не завершаем
+	var completeErr error
+	committed, err := e.store.Commit(ctx, key, message.Topic, message.Value, def.CanTransition, func(ctx context.Context) error {
+		completeErr = def.Complete(ctx, key, message.Value)
+		return completeErr
+	})
+	switch {
+	case completeErr != nil:
+		slog.ErrorContext(ctx, "failed to commit order, compensating", "error", completeErr)
+		return e.fail(ctx, def, key, len(def.Steps), def.CommitFailedReason, message, model.StepDetails{})
+	case errors.Is(err, repository.ErrSagaNotFound):
+		slog.WarnContext(ctx, "saga not found, processing without state")
+		return def.Complete(ctx, key, message.Value)
+	case err != nil:
+		return err
+	case !committed:
+		slog.InfoContext(ctx, "saga can't be committed, skip commit")
+		return nil
+	}
+
+	slog.InfoContext(ctx, "order committed")
+	e.finished(ctx, def, key, outcomeCommitted, "")
+	return nil
+}
+
+// отменяем сагу, получившую отказ на шаге index или при коммите, и откатываем шаги до него.
+// Если сага уже не ждет этого ответа, сообщение повторное или запоздало, и его пропускаем
+func (e *Engine) fail(ctx context.Context, def *Definition, key int64, index int, reason string, message *sarama.ConsumerMessage, details model.StepDetails) error {
+	state, compensations := def.failure(index)
+
+	cancelled, err := e.store.Cancel(ctx, key, state, message.Topic, len(compensations) > 0, details)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		if _, err := e.store.GetSaga(ctx, key); !errors.Is(err, repository.ErrSagaNotFound) {
+			slog.InfoContext(ctx, "saga is not waiting for this reply, skip", "expected_state", state)
+			return err
+		}
+		slog.WarnContext(ctx, "saga not found, processing without state")
+	}
+
+	slog.InfoContext(ctx, "saga failed, cancelling", "state", state, "reason", reason)
+	if cancelled {
+		e.finished(ctx, def, key, outcomeCancelled, reason)
+	}
+	if err := def.Cancel(ctx, key, reason); err != nil {
+		slog.ErrorContext(ctx, "failed to cancel order", "reason", reason, "error", err)
+	}
+
+	if len(compensations) == 0 {
+		return nil
+	}
+	return e.compensate(ctx, def, key, compensations, message.Topic, message.Value)
+}
+
+// фиксируем переход саги, false означает что переход недопустим и сообщение нужно пропустить
+func (e *Engine) transition(ctx context.Context, def *Definition, key int64, state string, message *sarama.ConsumerMessage, details model.StepDetails) (bool, error) {
+	err := e.store.Transition(ctx, key, state, message.Topic, message.Value, def.waitTimeout(state), details, def.CanTransition)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, repository.ErrInvalidTransition):
+		slog.InfoContext(ctx, "skip message", "error", err)
+		return false, nil
+	case errors.Is(err, repository.ErrSagaNotFound):
+		slog.WarnContext(ctx, "saga not found, processing without state")
+		return true, nil
+	}
+	return false, err
+}
+
+// отправляем сообщение саги key, в конверте оно ссылается на обрабатываемое сообщение
+func (e *Engine) send(ctx context.Context, key int64, topic string, payload []byte) error {
+	if err := kafka.SendMessage(kafka.WithSagaID(ctx, key), e.producer, topic, payload); err != nil {
+		slog.ErrorContext(ctx, "failed to send message", "send_topic", topic, "error", err)
+		return fmt.Errorf("failed to send %s message: %w", topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package saga
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// исходы саги в метриках
+const (
+	outcomeCommitted = "committed"
+	outcomeCancelled = "cancelled"
+	outcomeResolved  = "resolved"
+)
+
+var (
+	sagaDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "saga_duration_seconds",
+		Help:    "Time from saga start to commit or cancellation.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+	}, []string{"saga", "outcome"})
+
+	sagaCancellations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "saga_cancellations_total",
+		Help: "Cancelled sagas by reason code.",
+	}, []string{"saga", "reason"})
+)
+
+// учитываем завершение саги key; вызывается один раз, после перехода в конечное состояние
+func (e *Engine) finished(ctx context.Context, def *Definition, key int64, outcome string, reason string) {
+	if outcome == outcomeCancelled {
+		sagaCancellations.WithLabelValues(def.Name, reason).Inc()
+	}
+
+	saga, err := e.store.GetSaga(ctx, key)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load saga to record duration", "order_id", key, "error", err)
+		return
+	}
+	sagaDuration.WithLabelValues(def.Name, outcome).Observe(time.Since(saga.CreatedAt).Seconds())
+}
@@ -0,0 +1,106 @@
+package saga
+
+import (
+	"context"
+	"log/slog"
+	"order_service/model"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/Iowel/app-saga-service/contracts/logging"
+)
+
+// TimeoutTopic - топик, которым помечаются переходы саги по таймауту
+const TimeoutTopic = "saga_timeout"
+
+// RunTimeoutScheduler периодически ищет саги с истекшим сроком шага и компенсирует их
+func (e *Engine) RunTimeoutScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.compensateOverdue(ctx)
+		}
+	}
+}
+
+func (e *Engine) compensateOverdue(ctx context.Context) {
+	sagas, err := e.store.ListOverdue(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list overdue sagas", "error", err)
+		return
+	}
+
+	for _, saga := range sagas {
+		ctx := logging.With(ctx, "order_id", saga.OrderID, "saga", saga.Name)
+
+		def, ok := e.defs[saga.Name]
+		if !ok {
+			slog.WarnContext(ctx, "unknown saga, skip timeout")
+			continue
+		}
+
+		if err := e.compensateTimeout(ctx, def, saga); err != nil {
+			slog.ErrorContext(ctx, "failed to compensate timed out saga", "error", err)
+		}
+	}
+}
+
+// отменяем сагу по таймауту и откатываем то, что успели сделать участники
+func (e *Engine) compensateTimeout(ctx context.Context, def *Definition, saga *model.SagaInstance) error {
+	// сага уже отменена, но компенсация в прошлый раз не прошла - повторяем только ее
+	if saga.State == model.SagaCancelled {
+		from, err := e.store.CancelOrigin(ctx, saga.OrderID)
+		if err != nil {
+			return err
+		}
+		_, err = e.compensateCancelled(ctx, def, saga.OrderID, from, TimeoutTopic, saga.Payload)
+		return err
+	}
+
+	_, _, err := e.cancel(ctx, def, saga, TimeoutTopic, def.TimeoutReason, true)
+	return err
+}
+
+// компенсируем успешный ответ на шаг index, пришедший после отмены саги
+func (e *Engine) compensateLateReply(ctx context.Context, def *Definition, index int, key int64, message *sarama.ConsumerMessage) error {
+	step := def.Steps[index]
+
+	// компенсация такого шага уже отправлена вслепую при отмене
+	if step.CompensateOnTimeout || len(step.Compensation) == 0 {
+		return nil
+	}
+
+	saga, err := e.store.GetSaga(ctx, key)
+	if err != nil {
+		return err
+	}
+	if saga.State != model.SagaCancelled {
+		return nil
+	}
+
+	// ответ компенсируем, только если сага отменена именно в ожидании этого шага
+	from, err := e.store.CancelOrigin(ctx, key)
+	if err != nil || from != def.stateBefore(index) {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "late reply for cancelled saga, compensating step")
+
+	for _, topic := range step.Compensation {
+		if err := e.send(ctx, key, topic, message.Value); err != nil {
+			return err
+		}
+	}
+
+	details := model.StepDetails{
+		Decision:      step.decision(true),
+		Compensations: step.Compensation,
+	}
+	_, err = e.transition(ctx, def, key, model.SagaCompensated, message, details)
+	return err
+}
@@ -2,9 +2,10 @@ package gapi
 
 import (
 	"os/user"
-	"product/protos"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
 )
 
-func convertOrder(user *user.User) *protos.Order {
-	return &protos.Order{}
+func convertOrder(user *user.User) *events.Order {
+	return &events.Order{}
 }
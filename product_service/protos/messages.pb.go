@@ -7,6 +7,7 @@
 package protos
 
 import (
+	events "github.com/Iowel/app-saga-service/contracts/events"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -22,234 +23,6 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type Order struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserID        int64                  `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,2,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
-	ProductSKU    int64                  `protobuf:"varint,3,opt,name=ProductSKU,proto3" json:"ProductSKU,omitempty"`
-	OrderID       int64                  `protobuf:"varint,4,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *Order) Reset() {
-	*x = Order{}
-	mi := &file_messages_proto_msgTypes[0]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *Order) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Order) ProtoMessage() {}
-
-func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[0]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use Order.ProtoReflect.Descriptor instead.
-func (*Order) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{0}
-}
-
-func (x *Order) GetUserID() int64 {
-	if x != nil {
-		return x.UserID
-	}
-	return 0
-}
-
-func (x *Order) GetTimestamp() int64 {
-	if x != nil {
-		return x.Timestamp
-	}
-	return 0
-}
-
-func (x *Order) GetProductSKU() int64 {
-	if x != nil {
-		return x.ProductSKU
-	}
-	return 0
-}
-
-func (x *Order) GetOrderID() int64 {
-	if x != nil {
-		return x.OrderID
-	}
-	return 0
-}
-
-func (x *Order) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
-}
-
-func (x *Order) GetReason() string {
-	if x != nil {
-		return x.Reason
-	}
-	return ""
-}
-
-type Product struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sku           int64                  `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
-	Price         int64                  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
-	Cnt           int64                  `protobuf:"varint,3,opt,name=cnt,proto3" json:"cnt,omitempty"`
-	Avatar        string                 `protobuf:"bytes,4,opt,name=avatar,proto3" json:"avatar,omitempty"`
-	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *Product) Reset() {
-	*x = Product{}
-	mi := &file_messages_proto_msgTypes[1]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *Product) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Product) ProtoMessage() {}
-
-func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[1]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use Product.ProtoReflect.Descriptor instead.
-func (*Product) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *Product) GetSku() int64 {
-	if x != nil {
-		return x.Sku
-	}
-	return 0
-}
-
-func (x *Product) GetPrice() int64 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
-func (x *Product) GetCnt() int64 {
-	if x != nil {
-		return x.Cnt
-	}
-	return 0
-}
-
-func (x *Product) GetAvatar() string {
-	if x != nil {
-		return x.Avatar
-	}
-	return ""
-}
-
-func (x *Product) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-type OrderWithProduct struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Order             *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
-	Product           *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
-	Available         bool                   `protobuf:"varint,3,opt,name=Available,proto3" json:"Available,omitempty"`
-	BalanceSufficient bool                   `protobuf:"varint,4,opt,name=balanceSufficient,proto3" json:"balanceSufficient,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
-}
-
-func (x *OrderWithProduct) Reset() {
-	*x = OrderWithProduct{}
-	mi := &file_messages_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *OrderWithProduct) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*OrderWithProduct) ProtoMessage() {}
-
-func (x *OrderWithProduct) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[2]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use OrderWithProduct.ProtoReflect.Descriptor instead.
-func (*OrderWithProduct) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *OrderWithProduct) GetOrder() *Order {
-	if x != nil {
-		return x.Order
-	}
-	return nil
-}
-
-func (x *OrderWithProduct) GetProduct() *Product {
-	if x != nil {
-		return x.Product
-	}
-	return nil
-}
-
-func (x *OrderWithProduct) GetAvailable() bool {
-	if x != nil {
-		return x.Available
-	}
-	return false
-}
-
-func (x *OrderWithProduct) GetBalanceSufficient() bool {
-	if x != nil {
-		return x.BalanceSufficient
-	}
-	return false
-}
-
 type GetAllProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -258,7 +31,7 @@ type GetAllProductsRequest struct {
 
 func (x *GetAllProductsRequest) Reset() {
 	*x = GetAllProductsRequest{}
-	mi := &file_messages_proto_msgTypes[3]
+	mi := &file_messages_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -270,7 +43,7 @@ func (x *GetAllProductsRequest) String() string {
 func (*GetAllProductsRequest) ProtoMessage() {}
 
 func (x *GetAllProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[3]
+	mi := &file_messages_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -283,19 +56,19 @@ func (x *GetAllProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllProductsRequest.ProtoReflect.Descriptor instead.
 func (*GetAllProductsRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{3}
+	return file_messages_proto_rawDescGZIP(), []int{0}
 }
 
 type GetAllProductsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	Products      []*events.Product      `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllProductsResponse) Reset() {
 	*x = GetAllProductsResponse{}
-	mi := &file_messages_proto_msgTypes[4]
+	mi := &file_messages_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -307,7 +80,7 @@ func (x *GetAllProductsResponse) String() string {
 func (*GetAllProductsResponse) ProtoMessage() {}
 
 func (x *GetAllProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[4]
+	mi := &file_messages_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,10 +93,10 @@ func (x *GetAllProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllProductsResponse.ProtoReflect.Descriptor instead.
 func (*GetAllProductsResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{4}
+	return file_messages_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *GetAllProductsResponse) GetProducts() []*Product {
+func (x *GetAllProductsResponse) GetProducts() []*events.Product {
 	if x != nil {
 		return x.Products
 	}
@@ -334,32 +107,12 @@ var File_messages_proto protoreflect.FileDescriptor
 
 const file_messages_proto_rawDesc = "" +
 	"\n" +
-	"\x0emessages.proto\x12\x06protos\x1a\x1cgoogle/api/annotations.proto\"\xa7\x01\n" +
-	"\x05Order\x12\x16\n" +
-	"\x06UserID\x18\x01 \x01(\x03R\x06UserID\x12\x1c\n" +
-	"\tTimestamp\x18\x02 \x01(\x03R\tTimestamp\x12\x1e\n" +
-	"\n" +
-	"ProductSKU\x18\x03 \x01(\x03R\n" +
-	"ProductSKU\x12\x18\n" +
-	"\aOrderID\x18\x04 \x01(\x03R\aOrderID\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\x12\x16\n" +
-	"\x06reason\x18\x06 \x01(\tR\x06reason\"o\n" +
-	"\aProduct\x12\x10\n" +
-	"\x03sku\x18\x01 \x01(\x03R\x03sku\x12\x14\n" +
-	"\x05price\x18\x02 \x01(\x03R\x05price\x12\x10\n" +
-	"\x03cnt\x18\x03 \x01(\x03R\x03cnt\x12\x16\n" +
-	"\x06avatar\x18\x04 \x01(\tR\x06avatar\x12\x12\n" +
-	"\x04name\x18\x05 \x01(\tR\x04name\"\xae\x01\n" +
-	"\x10OrderWithProduct\x12#\n" +
-	"\x05order\x18\x01 \x01(\v2\r.protos.OrderR\x05order\x12)\n" +
-	"\aproduct\x18\x02 \x01(\v2\x0f.protos.ProductR\aproduct\x12\x1c\n" +
-	"\tAvailable\x18\x03 \x01(\bR\tAvailable\x12,\n" +
-	"\x11balanceSufficient\x18\x04 \x01(\bR\x11balanceSufficient\"\x17\n" +
+	"\x0emessages.proto\x12\x06protos\x1a\x1cgoogle/api/annotations.proto\x1a\x15events/messages.proto\"\x17\n" +
 	"\x15GetAllProductsRequest\"E\n" +
 	"\x16GetAllProductsResponse\x12+\n" +
-	"\bproducts\x18\x01 \x03(\v2\x0f.protos.ProductR\bproducts2u\n" +
+	"\bproducts\x18\x01 \x03(\v2\x0f.events.ProductR\bproducts2u\n" +
 	"\fOrderService\x12e\n" +
-	"\x0eGetAllProducts\x12\x1d.protos.GetAllProductsRequest\x1a\x1e.protos.GetAllProductsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/productsB\x16Z\x14orders/protos;protosb\x06proto3"
+	"\x0eGetAllProducts\x12\x1d.protos.GetAllProductsRequest\x1a\x1e.protos.GetAllProductsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/productsB\x17Z\x15product/protos;protosb\x06proto3"
 
 var (
 	file_messages_proto_rawDescOnce sync.Once
@@ -373,25 +126,21 @@ func file_messages_proto_rawDescGZIP() []byte {
 	return file_messages_proto_rawDescData
 }
 
-var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_messages_proto_goTypes = []any{
-	(*Order)(nil),                  // 0: protos.Order
-	(*Product)(nil),                // 1: protos.Product
-	(*OrderWithProduct)(nil),       // 2: protos.OrderWithProduct
-	(*GetAllProductsRequest)(nil),  // 3: protos.GetAllProductsRequest
-	(*GetAllProductsResponse)(nil), // 4: protos.GetAllProductsResponse
+	(*GetAllProductsRequest)(nil),  // 0: protos.GetAllProductsRequest
+	(*GetAllProductsResponse)(nil), // 1: protos.GetAllProductsResponse
+	(*events.Product)(nil),         // 2: events.Product
 }
 var file_messages_proto_depIdxs = []int32{
-	0, // 0: protos.OrderWithProduct.order:type_name -> protos.Order
-	1, // 1: protos.OrderWithProduct.product:type_name -> protos.Product
-	1, // 2: protos.GetAllProductsResponse.products:type_name -> protos.Product
-	3, // 3: protos.OrderService.GetAllProducts:input_type -> protos.GetAllProductsRequest
-	4, // 4: protos.OrderService.GetAllProducts:output_type -> protos.GetAllProductsResponse
-	4, // [4:5] is the sub-list for method output_type
-	3, // [3:4] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2, // 0: protos.GetAllProductsResponse.products:type_name -> events.Product
+	0, // 1: protos.OrderService.GetAllProducts:input_type -> protos.GetAllProductsRequest
+	1, // 2: protos.OrderService.GetAllProducts:output_type -> protos.GetAllProductsResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -405,7 +154,7 @@ func file_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messages_proto_rawDesc), len(file_messages_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -3,5 +3,6 @@ package repository
 import "errors"
 
 var (
-	ErrProductNotFound = errors.New("product not found")
+	ErrProductNotFound  = errors.New("product not found")
+	ErrAlreadyProcessed = errors.New("message already processed")
 )
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"product/protos"
+	"log/slog"
+	"sort"
 	"time"
 
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"github.com/Iowel/app-saga-service/contracts/outbox"
+	"github.com/Iowel/app-saga-service/contracts/topics"
 	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -25,8 +29,8 @@ func NewStockProductRepository(db *Db) *StockProductRepository {
 	return &StockProductRepository{db: db}
 }
 
-func (s *StockProductRepository) GetProduct(id int) (*protos.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *StockProductRepository) GetProduct(ctx context.Context, id int) (*events.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	query :=
@@ -37,13 +41,13 @@ func (s *StockProductRepository) GetProduct(id int) (*protos.Product, error) {
 	WHERE
 		sku = $1`
 
-	row := s.db.Pool.QueryRow(ctx, query, id)
+	row := s.db.Conn(ctx).QueryRow(ctx, query, id)
 
-	var product protos.Product
+	var product events.Product
 	err := row.Scan(&product.Sku, &product.Price, &product.Cnt, &product.Name)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			log.Printf("product with id %d not found", id)
+			slog.WarnContext(ctx, "product not found", "sku", id)
 			return nil, err
 
 		}
@@ -53,22 +57,22 @@ func (s *StockProductRepository) GetProduct(id int) (*protos.Product, error) {
 	return &product, nil
 }
 
-func (u *StockProductRepository) DeleteProductCount(id int64) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (u *StockProductRepository) DeleteProductCount(ctx context.Context, id int64, quantity int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	query := `
 		UPDATE
 			products
 		SET
-			cnt = cnt - 1
+			cnt = cnt - $2
 		WHERE
-			sku = $1 AND cnt > 0
+			sku = $1 AND cnt >= $2
 		RETURNING
 			cnt;`
 
 	var newCount int64
-	err := u.db.Pool.QueryRow(ctx, query, id).Scan(&newCount)
+	err := u.db.Conn(ctx).QueryRow(ctx, query, id, quantity).Scan(&newCount)
 	if err != nil {
 		return fmt.Errorf("failed to delete count: %v", err)
 	}
@@ -76,22 +80,130 @@ func (u *StockProductRepository) DeleteProductCount(id int64) error {
 	return nil
 }
 
-func (u *StockProductRepository) BackProductCount(sku int64) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+// ReserveProduct резервирует все позиции заказа на время ttl, если каждой хватает на складе с учетом
+// активных резервов других заказов, и сохраняет событие product_checked в outbox.
+// Если хотя бы одной позиции не хватает, не резервируется ничего.
+// Повторная проверка того же заказа не создает второй резерв и отвечает по первому.
+// Резерв и событие фиксируются в одной транзакции, поэтому склад и сага не могут разойтись.
+func (u *StockProductRepository) ReserveProduct(ctx context.Context, order *events.Order, ttl time.Duration) (*events.OrderWithProduct, error) {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = WithTx(ctx, tx)
+
+	before, err := reservedBefore(ctx, tx, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := OrderItems(order)
+
+	// блокируем строки в порядке sku, чтобы параллельные заказы не зарезервировали последний товар дважды
+	// и не взяли блокировки навстречу друг другу
+	locked := make(map[int64]*events.Product, len(items))
+	for _, item := range sortedBySku(items) {
+		if _, ok := locked[item.Sku]; ok {
+			continue
+		}
+
+		var product events.Product
+		err = tx.QueryRow(ctx, `
+			SELECT
+				sku, price, cnt, name
+			FROM
+				products
+			WHERE
+				sku = $1
+			FOR UPDATE`, item.Sku).Scan(&product.Sku, &product.Price, &product.Cnt, &product.Name)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("%w: sku %d", ErrProductNotFound, item.Sku)
+			}
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+
+		// в наличии то, что не зарезервировано другими заказами
+		reserved, err := reservedCount(ctx, tx, item.Sku)
+		if err != nil {
+			return nil, err
+		}
+		product.Cnt -= reserved
+
+		locked[item.Sku] = &product
+	}
+
+	// одинаковые позиции резервируем одной строкой
+	quantities := make(map[int64]int64, len(items))
+	for _, item := range items {
+		quantities[item.Sku] += item.Quantity
+	}
+
+	// при повторной проверке ответ повторяет уже сделанный резерв, а не считается по остатку,
+	// в котором резерв этого заказа учтен как чужой
+	available := before != reservedReleased
+	var total int64
+	products := make([]*events.Product, 0, len(items))
+	for _, item := range items {
+		product := locked[item.Sku]
+		if before == reservedNone && product.Cnt < quantities[item.Sku] {
+			available = false
+		}
+		total += product.Price * item.Quantity
+		products = append(products, product)
+	}
+
+	if available && before == reservedNone {
+		for sku, quantity := range quantities {
+			if err := insertReservation(ctx, tx, order.OrderID, sku, quantity, ttl); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// сообщение с продуктами + флаг доступности
+	result := &events.OrderWithProduct{
+		Order:     order,
+		Product:   products[0],
+		Products:  products,
+		Total:     total,
+		Available: available,
+	}
+
+	data, err := proto.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal product_checked: %w", err)
+	}
+
+	if err := outbox.Insert(ctx, tx, topics.ProductChecked, data); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+func (u *StockProductRepository) BackProductCount(ctx context.Context, sku int64, quantity int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	query := `
 		UPDATE
 			products
 		SET
-			cnt = cnt + 1
+			cnt = cnt + $2
 		WHERE
-			sku = $1 AND cnt > 0
+			sku = $1
 		RETURNING
 			cnt;`
 
 	var newCount int64
-	err := u.db.Pool.QueryRow(ctx, query, sku).Scan(&newCount)
+	err := u.db.Conn(ctx).QueryRow(ctx, query, sku, quantity).Scan(&newCount)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -103,12 +215,41 @@ func (u *StockProductRepository) BackProductCount(sku int64) error {
 	return nil
 }
 
-func (u *StockProductRepository) GetAllProducts(ctx context.Context) ([]*protos.Product, error) {
+// OrderItems возвращает позиции заказа, заказ без позиций считается одной единицей ProductSKU
+func OrderItems(order *events.Order) []*events.OrderItem {
+	if len(order.Items) > 0 {
+		return order.Items
+	}
+	return []*events.OrderItem{{Sku: order.ProductSKU, Quantity: 1}}
+}
+
+func sortedBySku(items []*events.OrderItem) []*events.OrderItem {
+	sorted := make([]*events.OrderItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Sku < sorted[j].Sku
+	})
+	return sorted
+}
+
+func (u *StockProductRepository) GetAllProducts(ctx context.Context) ([]*events.Product, error) {
 
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	query := `SELECT sku, price, cnt, avatar, name FROM products`
+	// показываем остаток за вычетом активных резервов
+	query := `
+		SELECT
+			p.sku, p.price, p.cnt - COALESCE(r.quantity, 0), p.avatar, p.name
+		FROM
+			products p
+			LEFT JOIN (
+				SELECT sku, SUM(quantity) AS quantity FROM reservations
+				WHERE status = 'active' AND expires_at > NOW()
+				GROUP BY sku
+			) r ON r.sku = p.sku
+		ORDER BY
+			p.sku`
 
 	rows, err := u.db.Pool.Query(ctx, query)
 	if err != nil {
@@ -116,9 +257,9 @@ func (u *StockProductRepository) GetAllProducts(ctx context.Context) ([]*protos.
 	}
 	defer rows.Close()
 
-	var products []*protos.Product
+	var products []*events.Product
 	for rows.Next() {
-		var p protos.Product
+		var p events.Product
 		err := rows.Scan(&p.Sku, &p.Price, &p.Cnt, &p.Avatar, &p.Name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
@@ -133,7 +274,7 @@ func (u *StockProductRepository) GetAllProducts(ctx context.Context) ([]*protos.
 	return products, nil
 }
 
-// func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) ([]*protos.Order, error) {
+// func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) ([]*events.Order, error) {
 // 	const query = `
 //         SELECT
 //             user_id,
@@ -152,9 +293,9 @@ func (u *StockProductRepository) GetAllProducts(ctx context.Context) ([]*protos.
 // 	}
 // 	defer rows.Close()
 
-// 	var orders []*protos.Order
+// 	var orders []*events.Order
 // 	for rows.Next() {
-// 		var order protos.Order
+// 		var order events.Order
 // 		if err := rows.Scan(
 // 			&order.UserID,
 // 			&order.Timestamp,
@@ -177,7 +318,7 @@ func (u *StockProductRepository) GetAllProducts(ctx context.Context) ([]*protos.
 
 ///////////////////////////
 
-// func (r *Repository) CreateOrder(ctx context.Context, order *protos.Order) error {
+// func (r *Repository) CreateOrder(ctx context.Context, order *events.Order) error {
 // 	data, err := proto.Marshal(order)
 // 	if err != nil {
 // 		return err
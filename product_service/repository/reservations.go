@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Iowel/app-saga-service/contracts/events"
+	"github.com/Iowel/app-saga-service/contracts/outbox"
+	"github.com/Iowel/app-saga-service/contracts/topics"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// статусы резерва
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// CommitReservation продает товар по резервам заказа и сохраняет в outbox ответ reservation_committed
+// с флагом Available, продажа и ответ фиксируются в одной транзакции. Возвращает false, если продать
+// не удалось: резерв уже снят компенсацией или истек, а товар успели зарезервировать другие заказы.
+// Тогда склад не меняется, а сага по ответу отменит заказ
+func (u *StockProductRepository) CommitReservation(ctx context.Context, product *events.OrderWithProduct) (bool, error) {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = WithTx(ctx, tx)
+
+	sold, err := u.SellReservation(ctx, product.GetOrder().GetOrderID())
+	if err != nil {
+		return false, err
+	}
+
+	reply := proto.Clone(product).(*events.OrderWithProduct)
+	reply.Available = sold
+	data, err := proto.Marshal(reply)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal reservation_committed: %w", err)
+	}
+	if err := outbox.Insert(ctx, tx, topics.ReservationCommitted, data); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return sold, nil
+}
+
+// SellReservation превращает резервы заказа в продажу: списывает товар со склада.
+// Истекший резерв продается, только если товара хватает с учетом активных резервов других заказов.
+// Заказ без резервов (товар списан до их появления) считается проданным.
+// Возвращает false, если продать нельзя, склад при этом не меняется
+func (u *StockProductRepository) SellReservation(ctx context.Context, orderID int64) (bool, error) {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = WithTx(ctx, tx)
+
+	reservations, err := lockReservations(ctx, tx, orderID, ReservationActive, ReservationExpired, ReservationCommitted, ReservationReleased)
+	if err != nil {
+		return false, err
+	}
+	if len(reservations) == 0 {
+		return true, nil
+	}
+
+	var pending []reservation
+	for _, r := range reservations {
+		switch {
+		case r.status == ReservationReleased:
+			// компенсация пришла раньше продажи
+			return false, nil
+		case r.status == ReservationCommitted:
+			continue
+		case r.expired:
+			// товар истекшего резерва могли забрать другие заказы, проверяем под блокировкой
+			available, err := availableCount(ctx, tx, r.sku)
+			if err != nil {
+				return false, err
+			}
+			if available < r.quantity {
+				return false, nil
+			}
+		}
+		pending = append(pending, r)
+	}
+
+	for _, r := range pending {
+		if err := u.DeleteProductCount(ctx, r.sku, r.quantity); err != nil {
+			return false, fmt.Errorf("failed to sell sku %d for order %d: %w", r.sku, orderID, err)
+		}
+	}
+
+	if err := setReservationStatus(ctx, tx, orderID, ReservationCommitted, ReservationActive, ReservationExpired); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// ReleaseReservation снимает резервы заказа, а если товар уже продан - возвращает его на склад.
+// Возвращает false, если резервов по заказу нет, тогда склад не меняется.
+func (u *StockProductRepository) ReleaseReservation(ctx context.Context, orderID int64) (bool, error) {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = WithTx(ctx, tx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM reservations WHERE order_id = $1)`, orderID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check reservations for order %d: %w", orderID, err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	sold, err := lockReservations(ctx, tx, orderID, ReservationCommitted)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range sold {
+		if err := u.BackProductCount(ctx, r.sku, r.quantity); err != nil {
+			return false, err
+		}
+	}
+
+	err = setReservationStatus(ctx, tx, orderID, ReservationReleased, ReservationActive, ReservationExpired, ReservationCommitted)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// ExpireReservations помечает истекшими резервы брошенных саг, товар снова становится доступен
+func (u *StockProductRepository) ExpireReservations(ctx context.Context) (int64, error) {
+	tag, err := u.db.Pool.Exec(ctx, `
+		UPDATE reservations
+		SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND expires_at < NOW()
+	`, ReservationExpired, ReservationActive)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire reservations: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// что уже зарезервировано по заказу к повторной проверке товара
+const (
+	reservedNone = iota
+	reservedActive
+	reservedReleased
+)
+
+// reservedBefore блокирует резервы заказа от прошлой проверки товара. Действующий или проданный
+// резерв не создается заново, снятый компенсацией - тоже. Истекший резерв удаляется,
+// и товар резервируется заново по текущему остатку
+func reservedBefore(ctx context.Context, q DBTX, orderID int64) (int, error) {
+	reservations, err := lockReservations(ctx, q, orderID, ReservationActive, ReservationExpired, ReservationCommitted, ReservationReleased)
+	if err != nil {
+		return reservedNone, err
+	}
+
+	expired := false
+	for _, r := range reservations {
+		switch {
+		case r.status == ReservationReleased:
+			return reservedReleased, nil
+		case r.status == ReservationCommitted:
+			return reservedActive, nil
+		case r.expired:
+			expired = true
+		}
+	}
+	if len(reservations) == 0 {
+		return reservedNone, nil
+	}
+	if !expired {
+		return reservedActive, nil
+	}
+
+	if _, err := q.Exec(ctx, `DELETE FROM reservations WHERE order_id = $1`, orderID); err != nil {
+		return reservedNone, fmt.Errorf("failed to delete expired reservations for order %d: %w", orderID, err)
+	}
+	return reservedNone, nil
+}
+
+type reservation struct {
+	sku      int64
+	quantity int64
+	status   string
+	// срок резерва прошел, даже если сборщик еще не пометил его expired
+	expired bool
+}
+
+// блокируем резервы заказа в указанных статусах
+func lockReservations(ctx context.Context, q DBTX, orderID int64, statuses ...string) ([]reservation, error) {
+	rows, err := q.Query(ctx, `
+		SELECT sku, quantity, status, status = $3 OR expires_at < NOW() FROM reservations
+		WHERE order_id = $1 AND status = ANY($2)
+		ORDER BY sku
+		FOR UPDATE
+	`, orderID, statuses, ReservationExpired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservations for order %d: %w", orderID, err)
+	}
+	defer rows.Close()
+
+	var reservations []reservation
+	for rows.Next() {
+		var r reservation
+		if err := rows.Scan(&r.sku, &r.quantity, &r.status, &r.expired); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return reservations, nil
+}
+
+func setReservationStatus(ctx context.Context, q DBTX, orderID int64, status string, from ...string) error {
+	_, err := q.Exec(ctx, `
+		UPDATE reservations SET status = $1, updated_at = NOW()
+		WHERE order_id = $2 AND status = ANY($3)
+	`, status, orderID, from)
+	if err != nil {
+		return fmt.Errorf("failed to set reservation status for order %d: %w", orderID, err)
+	}
+	return nil
+}
+
+// сколько товара sku на складе не занято активными резервами, строка товара блокируется
+func availableCount(ctx context.Context, q DBTX, sku int64) (int64, error) {
+	var cnt int64
+	err := q.QueryRow(ctx, `SELECT cnt FROM products WHERE sku = $1 FOR UPDATE`, sku).Scan(&cnt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%w: sku %d", ErrProductNotFound, sku)
+		}
+		return 0, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	reserved, err := reservedCount(ctx, q, sku)
+	if err != nil {
+		return 0, err
+	}
+	return cnt - reserved, nil
+}
+
+// сколько товара sku зарезервировано активными резервами
+func reservedCount(ctx context.Context, q DBTX, sku int64) (int64, error) {
+	var reserved int64
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(SUM(quantity), 0) FROM reservations
+		WHERE sku = $1 AND status = $2 AND expires_at > NOW()
+	`, sku, ReservationActive).Scan(&reserved)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reserved count for sku %d: %w", sku, err)
+	}
+	return reserved, nil
+}
+
+func insertReservation(ctx context.Context, q DBTX, orderID int64, sku int64, quantity int64, ttl time.Duration) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO reservations (order_id, sku, quantity, status, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + $5::BIGINT * INTERVAL '1 millisecond')
+	`, orderID, sku, quantity, ReservationActive, ttl.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to insert reservation for order %d: %w", orderID, err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "errors"
+
+var (
+	ErrAlreadyProcessed  = errors.New("message already processed")
+	ErrOrderRefunded     = errors.New("order already refunded")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrHoldNotFound      = errors.New("hold not found")
+)
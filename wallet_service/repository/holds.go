@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// статусы холда
+const (
+	HoldHeld     = "held"
+	HoldCaptured = "captured"
+	HoldReleased = "released"
+	HoldExpired  = "expired"
+	HoldRefunded = "refunded"
+)
+
+// HoldOrder резервирует сумму заказа на кошельке пользователя до списания или отмены.
+// Деньги остаются на кошельке, но пока холд активен, не входят в доступный баланс.
+// Повторный холд по заказу ничего не делает, холд по отмененному заказу не создается.
+func (u *BalanceRepository) HoldOrder(ctx context.Context, orderID int64, amount int, userId int, ttl time.Duration) error {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// блокируем кошелек, чтобы параллельные холды одного пользователя не превысили баланс
+	var wallet int
+	err = tx.QueryRow(ctx, `SELECT wallet FROM profiles WHERE user_id = $1 FOR UPDATE`, userId).Scan(&wallet)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: no wallet for user %d", ErrInsufficientFunds, userId)
+		}
+		return fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM wallet_holds WHERE order_id = $1`, orderID).Scan(&status)
+	switch {
+	case err == nil:
+		// отмена пришла раньше проверки баланса - сага уже отменена
+		if status == HoldReleased || status == HoldRefunded {
+			return ErrOrderRefunded
+		}
+		return nil
+	case !errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("failed to get hold for order %d: %w", orderID, err)
+	}
+
+	var held int
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM wallet_holds
+		WHERE user_id = $1 AND status = $2 AND expires_at > NOW()
+	`, userId, HoldHeld).Scan(&held)
+	if err != nil {
+		return fmt.Errorf("failed to get holds for user %d: %w", userId, err)
+	}
+
+	if wallet-held < amount {
+		return fmt.Errorf("%w: wallet %d, held %d, need %d", ErrInsufficientFunds, wallet, held, amount)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO wallet_holds (order_id, user_id, amount, status, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + $5::BIGINT * INTERVAL '1 millisecond')
+	`, orderID, userId, amount, HoldHeld, ttl.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to insert hold for order %d: %w", orderID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CaptureOrder списывает зарезервированную сумму с кошелька и записывает списание за заказом.
+// Истекший холд списывается, только если свободных денег на кошельке хватает, иначе
+// возвращается ErrInsufficientFunds и холд остается как был. Возвращает списанную сумму.
+func (u *BalanceRepository) CaptureOrder(ctx context.Context, orderID int64) (int, error) {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = WithTx(ctx, tx)
+
+	var userId, amount int
+	var status string
+	var expired bool
+	err = tx.QueryRow(ctx, `
+		SELECT user_id, amount, status, expires_at < NOW() FROM wallet_holds WHERE order_id = $1 FOR UPDATE
+	`, orderID).Scan(&userId, &amount, &status, &expired)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrHoldNotFound
+		}
+		return 0, fmt.Errorf("failed to get hold for order %d: %w", orderID, err)
+	}
+
+	switch status {
+	case HoldCaptured:
+		return amount, nil
+	case HoldReleased, HoldRefunded:
+		return 0, ErrOrderRefunded
+	case HoldExpired:
+		expired = true
+	}
+
+	// деньги истекшего холда могли уйти в холды других заказов
+	if expired {
+		available, err := availableFunds(ctx, tx, userId)
+		if err != nil {
+			return 0, err
+		}
+		if available < amount {
+			return 0, fmt.Errorf("%w: hold expired, available %d, need %d", ErrInsufficientFunds, available, amount)
+		}
+	}
+
+	if err := u.DeleteUserPrice(ctx, amount, userId); err != nil {
+		return 0, fmt.Errorf("failed to capture %d for order %d: %w", amount, orderID, err)
+	}
+
+	if err := setHoldStatus(ctx, tx, orderID, HoldCaptured); err != nil {
+		return 0, err
+	}
+
+	if err := insertOperation(ctx, tx, orderID, userId, amount, OperationDebit); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return amount, nil
+}
+
+// ReleaseOrder отменяет холд по заказу, а если деньги уже списаны - возвращает их.
+// Если холда еще нет, записывает отмененный холд, чтобы запоздавшая проверка баланса его не создала.
+// Возвращает сумму, вернувшуюся на кошелек.
+func (u *BalanceRepository) ReleaseOrder(ctx context.Context, orderID int64, userId int) (int, error) {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = WithTx(ctx, tx)
+
+	var amount int
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT user_id, amount, status FROM wallet_holds WHERE order_id = $1 FOR UPDATE
+	`, orderID).Scan(&userId, &amount, &status)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to get hold for order %d: %w", orderID, err)
+	}
+
+	refunded := 0
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// заказ оплачен по старой схеме, сразу списанием
+		var debited bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM wallet_operations WHERE order_id = $1 AND kind = $2)
+		`, orderID, OperationDebit).Scan(&debited)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check debit for order %d: %w", orderID, err)
+		}
+		if debited {
+			if refunded, err = u.RefundOrder(ctx, orderID, userId); err != nil {
+				return 0, err
+			}
+			break
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO wallet_holds (order_id, user_id, amount, status, expires_at)
+			VALUES ($1, $2, 0, $3, NOW())
+		`, orderID, userId, HoldReleased)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert released hold for order %d: %w", orderID, err)
+		}
+	case status == HoldHeld || status == HoldExpired:
+		if err := setHoldStatus(ctx, tx, orderID, HoldReleased); err != nil {
+			return 0, err
+		}
+	case status == HoldCaptured:
+		if err := u.BackUserPrice(ctx, amount, userId); err != nil {
+			return 0, err
+		}
+		if err := setHoldStatus(ctx, tx, orderID, HoldRefunded); err != nil {
+			return 0, err
+		}
+		if err := insertOperation(ctx, tx, orderID, userId, amount, OperationRefund); err != nil {
+			return 0, err
+		}
+		refunded = amount
+	default:
+		return 0, ErrOrderRefunded
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return refunded, nil
+}
+
+// ExpireHolds помечает истекшими холды, которые не были ни списаны, ни отменены.
+// Зарезервированная сумма снова становится доступной, деньги с кошелька при этом не уходили.
+func (u *BalanceRepository) ExpireHolds(ctx context.Context) (int64, error) {
+	tag, err := u.db.Pool.Exec(ctx, `
+		UPDATE wallet_holds
+		SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND expires_at < NOW()
+	`, HoldExpired, HoldHeld)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire holds: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// свободные деньги пользователя: кошелек без активных холдов, кошелек блокируется до конца транзакции
+func availableFunds(ctx context.Context, q DBTX, userId int) (int, error) {
+	var wallet int
+	err := q.QueryRow(ctx, `SELECT wallet FROM profiles WHERE user_id = $1 FOR UPDATE`, userId).Scan(&wallet)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	var held int
+	err = q.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM wallet_holds
+		WHERE user_id = $1 AND status = $2 AND expires_at > NOW()
+	`, userId, HoldHeld).Scan(&held)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get holds for user %d: %w", userId, err)
+	}
+
+	return wallet - held, nil
+}
+
+func setHoldStatus(ctx context.Context, q DBTX, orderID int64, status string) error {
+	_, err := q.Exec(ctx, `
+		UPDATE wallet_holds SET status = $1, updated_at = NOW() WHERE order_id = $2
+	`, status, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to set hold status for order %d: %w", orderID, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// InboxRepository хранит обработанные сообщения, чтобы повторная доставка из kafka не повторяла побочные эффекты
+type InboxRepository struct {
+	db      *Db
+	service string
+}
+
+func NewInboxRepository(db *Db, service string) *InboxRepository {
+	return &InboxRepository{db: db, service: service}
+}
+
+// Process выполняет fn, если сообщение шага step для заказа orderID еще не обрабатывалось.
+// Отметка об обработке и изменения, сделанные fn через переданный контекст, фиксируются в одной транзакции.
+// Для уже обработанного сообщения возвращает ErrAlreadyProcessed.
+func (r *InboxRepository) Process(ctx context.Context, orderID int64, step string, fn func(ctx context.Context) error) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// вставка блокирует ключ, поэтому параллельный дубль дождется окончания обработки
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO processed_messages (service, order_id, step)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (service, order_id, step) DO NOTHING
+	`, r.service, orderID, step)
+	if err != nil {
+		return fmt.Errorf("failed to mark message %s for order %d: %w", step, orderID, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyProcessed
+	}
+
+	if err := fn(WithTx(ctx, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/Iowel/app-saga-service/contracts/outbox"
+	"github.com/jackc/pgx/v5"
 )
 
 const (
@@ -12,6 +16,12 @@ const (
 	OrderCanceled
 )
 
+// виды операций по кошельку
+const (
+	OperationDebit  = "debit"
+	OperationRefund = "refund"
+)
+
 type BalanceRepository struct {
 	db *Db
 }
@@ -20,8 +30,14 @@ func NewBalanceRepository(db *Db) *BalanceRepository {
 	return &BalanceRepository{db: db}
 }
 
-func (u *BalanceRepository) DeleteUserPrice(price int, userId int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+// SaveReply сохраняет ответ саге в outbox в транзакции из ctx, поэтому ответ публикуется,
+// только если изменения кошелька зафиксированы
+func (u *BalanceRepository) SaveReply(ctx context.Context, topic string, payload []byte) error {
+	return outbox.Insert(ctx, u.db.Conn(ctx), topic, payload)
+}
+
+func (u *BalanceRepository) DeleteUserPrice(ctx context.Context, price int, userId int) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	query := `
@@ -31,7 +47,7 @@ func (u *BalanceRepository) DeleteUserPrice(price int, userId int) error {
 		RETURNING wallet;`
 
 	var newPrice int
-	err := u.db.Pool.QueryRow(ctx, query, price, userId).Scan(&newPrice)
+	err := u.db.Conn(ctx).QueryRow(ctx, query, price, userId).Scan(&newPrice)
 	if err != nil {
 		return err
 	}
@@ -39,8 +55,8 @@ func (u *BalanceRepository) DeleteUserPrice(price int, userId int) error {
 	return nil
 }
 
-func (u *BalanceRepository) BackUserPrice(price int, userId int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (u *BalanceRepository) BackUserPrice(ctx context.Context, price int, userId int) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	// обнлвляем значение wallet добавляя price
@@ -51,10 +67,68 @@ func (u *BalanceRepository) BackUserPrice(price int, userId int) error {
 		RETURNING wallet;`
 
 	var newPrice int
-	err := u.db.Pool.QueryRow(ctx, query, price, userId).Scan(&newPrice)
+	err := u.db.Conn(ctx).QueryRow(ctx, query, price, userId).Scan(&newPrice)
 	if err != nil {
 		return fmt.Errorf("failed to add count wallet: %v", err)
 	}
 
 	return nil
 }
+
+// RefundOrder возвращает деньги, списанные за заказ до появления холдов, и записывает возврат за заказом.
+// Если списания не было, записывает пустой возврат, чтобы запоздавшая проверка баланса не списала деньги.
+// Возвращает сумму возврата.
+func (u *BalanceRepository) RefundOrder(ctx context.Context, orderID int64, userId int) (int, error) {
+	tx, err := u.db.Conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = WithTx(ctx, tx)
+
+	var amount int
+	err = tx.QueryRow(ctx, `
+		SELECT amount, user_id FROM wallet_operations WHERE order_id = $1 AND kind = $2
+	`, orderID, OperationDebit).Scan(&amount, &userId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to get debit for order %d: %w", orderID, err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO wallet_operations (order_id, user_id, amount, kind)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (order_id, kind) DO NOTHING
+	`, orderID, userId, amount, OperationRefund)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert refund for order %d: %w", orderID, err)
+	}
+
+	// возврат по заказу уже был
+	if tag.RowsAffected() == 0 {
+		return 0, ErrOrderRefunded
+	}
+
+	if amount > 0 {
+		if err := u.BackUserPrice(ctx, amount, userId); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed commit transaction: %w", err)
+	}
+
+	return amount, nil
+}
+
+func insertOperation(ctx context.Context, q DBTX, orderID int64, userId int, amount int, kind string) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO wallet_operations (order_id, user_id, amount, kind)
+		VALUES ($1, $2, $3, $4)
+	`, orderID, userId, amount, kind)
+	if err != nil {
+		return fmt.Errorf("failed to insert %s for order %d: %w", kind, orderID, err)
+	}
+	return nil
+}